@@ -5,9 +5,12 @@ import (
 
 	"github.com/grafana/grafana/pkg/api/dtos"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/middleware"
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/tsdb"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 func ValidateOrgAlert(c *middleware.Context) {
@@ -152,7 +155,61 @@ func GetAlert(c *middleware.Context) Response {
 		return ApiError(500, "List alerts failed", err)
 	}
 
-	return Json(200, &query.Result)
+	instancesQuery := models.GetAlertInstancesQuery{OrgId: query.Result.OrgId, AlertId: id}
+	if err := bus.Dispatch(&instancesQuery); err != nil {
+		return ApiError(500, "Failed to get alert instances", err)
+	}
+
+	return Json(200, &dtos.AlertDetail{Alert: query.Result, Instances: instancesQuery.Result})
+}
+
+// GET /api/alerts/history
+func GetAlertStateHistory(c *middleware.Context) Response {
+	return getAlertStateHistory(c, 0)
+}
+
+// GET /api/alerts/:alertId/history
+func GetAlertStateHistoryForAlert(c *middleware.Context) Response {
+	return getAlertStateHistory(c, c.ParamsInt64(":alertId"))
+}
+
+func getAlertStateHistory(c *middleware.Context, alertId int64) Response {
+	query := models.GetAlertStateHistoryQuery{
+		OrgId:       c.OrgId,
+		AlertId:     alertId,
+		DashboardId: c.QueryInt64("dashboardId"),
+		NodeId:      c.Query("nodeId"),
+		State:       c.QueryStrings("state"),
+		From:        c.QueryInt64("from") / 1000,
+		To:          c.QueryInt64("to") / 1000,
+		Before:      c.QueryInt64("before"),
+		Limit:       c.QueryInt("limit"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert state history", err)
+	}
+
+	result := &dtos.AlertStateHistory{
+		Items:      make([]*dtos.AlertStateHistoryItem, 0),
+		NextCursor: query.NextCursor,
+	}
+	for _, entry := range query.Result {
+		result.Items = append(result.Items, &dtos.AlertStateHistoryItem{
+			Id:          entry.Id,
+			AlertId:     entry.AlertId,
+			AlertName:   entry.AlertName,
+			DashboardId: entry.DashboardId,
+			PanelId:     entry.PanelId,
+			NodeId:      entry.NodeId,
+			PrevState:   entry.PrevState,
+			NewState:    entry.NewState,
+			Time:        entry.Epoch * 1000,
+			Data:        entry.Data,
+		})
+	}
+
+	return Json(200, result)
 }
 
 // DEL /api/alerts/:id
@@ -187,14 +244,7 @@ func GetAlertNotifications(c *middleware.Context) Response {
 	result := make([]*dtos.AlertNotification, 0)
 
 	for _, notification := range query.Result {
-		result = append(result, &dtos.AlertNotification{
-			Id:        notification.Id,
-			Name:      notification.Name,
-			Type:      notification.Type,
-			IsDefault: notification.IsDefault,
-			Created:   notification.Created,
-			Updated:   notification.Updated,
-		})
+		result = append(result, dtos.NewAlertNotification(notification))
 	}
 
 	return Json(200, result)
@@ -210,27 +260,66 @@ func GetAlertNotificationById(c *middleware.Context) Response {
 		return ApiError(500, "Failed to get alert notifications", err)
 	}
 
-	return Json(200, query.Result)
+	if query.Result == nil {
+		return ApiError(404, "Alert notification not found", nil)
+	}
+
+	return Json(200, newAlertNotificationWithSettings(query.Result))
 }
 
 func CreateAlertNotification(c *middleware.Context, cmd models.CreateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if err := validateNotificationTemplate(cmd.Settings); err != nil {
+		return ApiError(400, "Invalid notification template", err)
+	}
+
+	if _, err := alerting.NewNotificationGroupSettings(cmd.Settings); err != nil {
+		return ApiError(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrNotificationFrequencyInvalid {
+			return ApiError(400, err.Error(), err)
+		}
 		return ApiError(500, "Failed to create alert notification", err)
 	}
 
-	return Json(200, cmd.Result)
+	return Json(200, newAlertNotificationWithSettings(cmd.Result))
 }
 
 func UpdateAlertNotification(c *middleware.Context, cmd models.UpdateAlertNotificationCommand) Response {
 	cmd.OrgId = c.OrgId
 
+	if err := validateNotificationTemplate(cmd.Settings); err != nil {
+		return ApiError(400, "Invalid notification template", err)
+	}
+
+	if _, err := alerting.NewNotificationGroupSettings(cmd.Settings); err != nil {
+		return ApiError(400, err.Error(), err)
+	}
+
 	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrNotificationFrequencyInvalid {
+			return ApiError(400, err.Error(), err)
+		}
 		return ApiError(500, "Failed to update alert notification", err)
 	}
 
-	return Json(200, cmd.Result)
+	return Json(200, newAlertNotificationWithSettings(cmd.Result))
+}
+
+func validateNotificationTemplate(settings *simplejson.Json) error {
+	if settings == nil {
+		return nil
+	}
+	return alerting.ValidateTemplate(settings.Get("template").MustString())
+}
+
+func newAlertNotificationWithSettings(notification *models.AlertNotification) *dtos.AlertNotification {
+	result := dtos.NewAlertNotification(notification)
+	result.Settings = notification.Settings
+	return result
 }
 
 func DeleteAlertNotification(c *middleware.Context) Response {
@@ -293,6 +382,276 @@ func PauseAlert(c *middleware.Context, dto dtos.PauseAlertCommand) Response {
 	return Json(200, result)
 }
 
+// POST /api/alerts/backtest
+func AlertBacktest(c *middleware.Context, dto dtos.AlertBacktestCommand) Response {
+	if dto.AlertId == 0 && dto.Dashboard == nil {
+		return ApiError(400, "Either an alert id or a dashboard and panel id is required", nil)
+	}
+
+	if dto.From == "" {
+		dto.From = "now-168h"
+	}
+	if dto.To == "" {
+		dto.To = "now"
+	}
+
+	timeRange := tsdb.NewTimeRange(dto.From, dto.To)
+	from, err := timeRange.ParseFrom()
+	if err != nil {
+		return ApiError(400, "Could not parse from", err)
+	}
+	to, err := timeRange.ParseTo()
+	if err != nil {
+		return ApiError(400, "Could not parse to", err)
+	}
+
+	cmd := alerting.BacktestCommand{
+		OrgId:     c.OrgId,
+		AlertId:   dto.AlertId,
+		Dashboard: dto.Dashboard,
+		PanelId:   dto.PanelId,
+		From:      from,
+		To:        to,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(422, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to backtest rule", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// POST /api/alerts/render-template
+func AlertRenderTemplate(c *middleware.Context, dto dtos.AlertRenderTemplateCommand) Response {
+	cmd := alerting.PreviewTemplateCommand{
+		OrgId:    c.OrgId,
+		AlertId:  dto.AlertId,
+		Template: dto.Template,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(422, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to render template", err)
+	}
+
+	return Json(200, util.DynMap{"text": cmd.Result})
+}
+
+// GET /api/alert-rules
+func GetAlertRules(c *middleware.Context) Response {
+	query := models.GetAlertRulesQuery{OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert rules", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// GET /api/alert-rules/:ruleId
+func GetAlertRule(c *middleware.Context) Response {
+	query := models.GetAlertRuleByIdQuery{Id: c.ParamsInt64(":ruleId"), OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		if err == models.ErrAlertRuleNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to get alert rule", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/alert-rules
+func CreateAlertRule(c *middleware.Context, dto dtos.SaveAlertRuleCommand) Response {
+	return saveAlertRule(c, 0, dto)
+}
+
+// PUT /api/alert-rules/:ruleId
+func UpdateAlertRule(c *middleware.Context, dto dtos.SaveAlertRuleCommand) Response {
+	return saveAlertRule(c, c.ParamsInt64(":ruleId"), dto)
+}
+
+func saveAlertRule(c *middleware.Context, id int64, dto dtos.SaveAlertRuleCommand) Response {
+	cmd := alerting.SaveAlertRuleCommand{
+		Id:     id,
+		OrgId:  c.OrgId,
+		UserId: c.UserId,
+		Rule:   dto.Rule,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(422, validationErr.Error(), nil)
+		}
+		if err == models.ErrAlertRuleNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to save alert rule", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// DELETE /api/alert-rules/:ruleId
+func DeleteAlertRule(c *middleware.Context) Response {
+	cmd := models.DeleteAlertRuleCommand{Id: c.ParamsInt64(":ruleId"), OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertRuleNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to delete alert rule", err)
+	}
+
+	return ApiSuccess("Alert rule deleted")
+}
+
+// GET /api/alert-notification-log
+func GetAlertNotificationLog(c *middleware.Context) Response {
+	query := models.GetAlertNotificationLogQuery{
+		OrgId:           c.OrgId,
+		AlertId:         c.QueryInt64("alertId"),
+		NotifierId:      c.QueryInt64("notifierId"),
+		IncludeReplayed: c.QueryBool("includeReplayed"),
+		Limit:           c.QueryInt("limit"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert notification log", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/alert-notification-log/:logId/replay
+func ReplayAlertNotification(c *middleware.Context) Response {
+	cmd := alerting.ReplayNotificationCommand{Id: c.ParamsInt64(":logId"), OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertNotificationLogNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		if validationErr, ok := err.(alerting.ValidationError); ok {
+			return ApiError(422, validationErr.Error(), nil)
+		}
+		return ApiError(500, "Failed to send notification", err)
+	}
+
+	return ApiSuccess("Notification sent")
+}
+
+// GET /api/alert-routing-policies
+func GetAlertRoutingPolicies(c *middleware.Context) Response {
+	query := models.GetAlertRoutingPoliciesQuery{OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert routing policies", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// GET /api/alert-routing-policies/:policyId
+func GetAlertRoutingPolicy(c *middleware.Context) Response {
+	query := models.GetAlertRoutingPolicyByIdQuery{Id: c.ParamsInt64(":policyId"), OrgId: c.OrgId}
+	if err := bus.Dispatch(&query); err != nil {
+		if err == models.ErrAlertRoutingPolicyNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to get alert routing policy", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/alert-routing-policies
+func CreateAlertRoutingPolicy(c *middleware.Context, cmd models.SaveAlertRoutingPolicyCommand) Response {
+	return saveAlertRoutingPolicy(c, 0, cmd)
+}
+
+// PUT /api/alert-routing-policies/:policyId
+func UpdateAlertRoutingPolicy(c *middleware.Context, cmd models.SaveAlertRoutingPolicyCommand) Response {
+	return saveAlertRoutingPolicy(c, c.ParamsInt64(":policyId"), cmd)
+}
+
+func saveAlertRoutingPolicy(c *middleware.Context, id int64, cmd models.SaveAlertRoutingPolicyCommand) Response {
+	cmd.Id = id
+	cmd.OrgId = c.OrgId
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		switch err {
+		case models.ErrAlertRoutingPolicyMissingTarget, models.ErrAlertRoutingPolicyInvalidPattern:
+			return ApiError(400, err.Error(), err)
+		case models.ErrAlertRoutingPolicyNotFound:
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to save alert routing policy", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// DELETE /api/alert-routing-policies/:policyId
+func DeleteAlertRoutingPolicy(c *middleware.Context) Response {
+	cmd := models.DeleteAlertRoutingPolicyCommand{Id: c.ParamsInt64(":policyId"), OrgId: c.OrgId}
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertRoutingPolicyNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to delete alert routing policy", err)
+	}
+
+	return ApiSuccess("Alert routing policy deleted")
+}
+
+// GET /api/alerts/silences
+func GetAlertSilences(c *middleware.Context) Response {
+	query := models.GetAlertSilencesQuery{
+		OrgId:  c.OrgId,
+		Active: c.QueryBool("active"),
+	}
+
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert silences", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/alerts/silences
+func CreateAlertSilence(c *middleware.Context, cmd models.CreateAlertSilenceCommand) Response {
+	cmd.OrgId = c.OrgId
+	cmd.UserId = c.UserId
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		switch err {
+		case models.ErrAlertSilenceMissingMatcher, models.ErrAlertSilenceInvalidPattern, models.ErrAlertSilenceInvalidWindow:
+			return ApiError(400, err.Error(), err)
+		}
+		return ApiError(500, "Failed to create alert silence", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
+// POST /api/alerts/silences/:silenceId/expire
+func ExpireAlertSilence(c *middleware.Context) Response {
+	cmd := models.ExpireAlertSilenceCommand{
+		Id:    c.ParamsInt64("silenceId"),
+		OrgId: c.OrgId,
+	}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		if err == models.ErrAlertSilenceNotFound {
+			return ApiError(404, err.Error(), nil)
+		}
+		return ApiError(500, "Failed to expire alert silence", err)
+	}
+
+	return Json(200, cmd.Result)
+}
+
 //POST /api/admin/pause-all-alerts
 func PauseAllAlerts(c *middleware.Context, dto dtos.PauseAllAlertsCommand) Response {
 	updateCmd := models.PauseAllAlertCommand{
@@ -268,12 +268,38 @@ func (hs *HttpServer) registerRoutes() {
 
 		r.Group("/alerts", func() {
 			r.Post("/test", bind(dtos.AlertTestCommand{}), wrap(AlertTest))
+			r.Post("/backtest", bind(dtos.AlertBacktestCommand{}), wrap(AlertBacktest))
+			r.Post("/render-template", bind(dtos.AlertRenderTemplateCommand{}), wrap(AlertRenderTemplate))
+			r.Get("/silences", wrap(GetAlertSilences))
+			r.Post("/silences", bind(m.CreateAlertSilenceCommand{}), wrap(CreateAlertSilence), reqEditorRole)
+			r.Post("/silences/:silenceId/expire", wrap(ExpireAlertSilence), reqEditorRole)
 			r.Post("/:alertId/pause", bind(dtos.PauseAlertCommand{}), wrap(PauseAlert), reqEditorRole)
+			r.Get("/history", wrap(GetAlertStateHistory))
+			r.Get("/:alertId/history", ValidateOrgAlert, wrap(GetAlertStateHistoryForAlert))
 			r.Get("/:alertId", ValidateOrgAlert, wrap(GetAlert))
 			r.Get("/", wrap(GetAlerts))
 			r.Get("/states-for-dashboard", wrap(GetAlertStatesForDashboard))
 		})
 
+		r.Group("/alert-rules", func() {
+			r.Get("/", wrap(GetAlertRules))
+			r.Get("/:ruleId", wrap(GetAlertRule))
+			r.Post("/", bind(dtos.SaveAlertRuleCommand{}), wrap(CreateAlertRule), reqEditorRole)
+			r.Put("/:ruleId", bind(dtos.SaveAlertRuleCommand{}), wrap(UpdateAlertRule), reqEditorRole)
+			r.Delete("/:ruleId", wrap(DeleteAlertRule), reqEditorRole)
+		})
+
+		r.Group("/alert-routing-policies", func() {
+			r.Get("/", wrap(GetAlertRoutingPolicies))
+			r.Get("/:policyId", wrap(GetAlertRoutingPolicy))
+			r.Post("/", bind(m.SaveAlertRoutingPolicyCommand{}), wrap(CreateAlertRoutingPolicy), reqEditorRole)
+			r.Put("/:policyId", bind(m.SaveAlertRoutingPolicyCommand{}), wrap(UpdateAlertRoutingPolicy), reqEditorRole)
+			r.Delete("/:policyId", wrap(DeleteAlertRoutingPolicy), reqEditorRole)
+		})
+
+		r.Get("/alert-notification-log", wrap(GetAlertNotificationLog))
+		r.Post("/alert-notification-log/:logId/replay", wrap(ReplayAlertNotification), reqEditorRole)
+
 		r.Get("/alert-notifications", wrap(GetAlertNotifications))
 		r.Get("/alert-notifiers", wrap(GetAlertNotifiers))
 
@@ -308,6 +334,10 @@ func (hs *HttpServer) registerRoutes() {
 		r.Put("/users/:id/quotas/:target", bind(m.UpdateUserQuotaCmd{}), wrap(UpdateUserQuota))
 		r.Get("/stats", AdminGetStats)
 		r.Post("/pause-all-alerts", bind(dtos.PauseAllAlertsCommand{}), wrap(PauseAllAlerts))
+		r.Get("/cluster", wrap(GetClusterStatus))
+		r.Get("/cluster/history", wrap(GetClusterNodeHistory))
+		r.Get("/cluster/executions", wrap(GetAlertExecutions))
+		r.Post("/cluster/drain", bind(dtos.DrainNodeCommand{}), wrap(DrainClusterNode))
 	}, reqGrafanaAdmin)
 
 	// rendering
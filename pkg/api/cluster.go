@@ -0,0 +1,129 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/middleware"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/clustering"
+)
+
+func newClusterNodeDTO(node *m.ActiveNode, lastCheckIn map[string]int64) *dtos.ClusterNode {
+	if node == nil {
+		return nil
+	}
+	return &dtos.ClusterNode{
+		NodeId:       node.NodeId,
+		PartId:       node.PartId,
+		AlertStatus:  node.AlertStatus,
+		AlertRunType: node.AlertRunType,
+		Heartbeat:    node.Heartbeat,
+		LastCheckIn:  lastCheckIn[node.NodeId],
+	}
+}
+
+// GET /api/admin/cluster
+func GetClusterStatus(c *middleware.Context) Response {
+	nodesQuery := m.GetLatestActiveNodesQuery{Heartbeat: c.QueryInt64("heartbeat")}
+	if err := bus.Dispatch(&nodesQuery); err != nil {
+		return ApiError(500, "Failed to get active nodes", err)
+	}
+
+	checkInQuery := m.GetActiveNodesLastCheckInQuery{}
+	if err := bus.Dispatch(&checkInQuery); err != nil {
+		return ApiError(500, "Failed to get last node check-in", err)
+	}
+
+	missingQuery := m.GetLastNodeForRunTypeQuery{AlertRunType: m.CLN_ALERT_RUN_TYPE_MISSING}
+	if err := bus.Dispatch(&missingQuery); err != nil {
+		return ApiError(500, "Failed to get node processing missing alerts", err)
+	}
+
+	cleanupQuery := m.GetLastNodeForRunTypeQuery{AlertRunType: m.CLN_ALERT_RUN_TYPE_CLEANUP}
+	if err := bus.Dispatch(&cleanupQuery); err != nil {
+		return ApiError(500, "Failed to get node running cleanup", err)
+	}
+
+	result := &dtos.ClusterStatus{
+		Heartbeat:         nodesQuery.Heartbeat,
+		Nodes:             make([]*dtos.ClusterNode, 0),
+		MissingAlertsNode: newClusterNodeDTO(missingQuery.Result, checkInQuery.Result),
+		CleanupNode:       newClusterNodeDTO(cleanupQuery.Result, checkInQuery.Result),
+	}
+
+	for _, node := range nodesQuery.Result {
+		result.Nodes = append(result.Nodes, newClusterNodeDTO(node, checkInQuery.Result))
+		if node.AlertStatus == m.CLN_ALERT_STATUS_READY && node.AlertRunType == m.CLN_ALERT_RUN_TYPE_NORMAL {
+			result.NodeCount++
+		}
+	}
+
+	return Json(200, result)
+}
+
+// GET /api/admin/cluster/history
+func GetClusterNodeHistory(c *middleware.Context) Response {
+	query := m.GetActiveNodeHistoryQuery{
+		NodeId:     c.Query("nodeId"),
+		Heartbeats: c.QueryInt("heartbeats"),
+	}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get cluster node history", err)
+	}
+
+	checkInQuery := m.GetActiveNodesLastCheckInQuery{}
+	if err := bus.Dispatch(&checkInQuery); err != nil {
+		return ApiError(500, "Failed to get last node check-in", err)
+	}
+
+	result := make([]*dtos.ClusterNodeHistory, 0)
+	byNode := make(map[string]*dtos.ClusterNodeHistory)
+	for _, node := range query.Result {
+		history, ok := byNode[node.NodeId]
+		if !ok {
+			history = &dtos.ClusterNodeHistory{NodeId: node.NodeId, History: make([]*dtos.ClusterNode, 0)}
+			byNode[node.NodeId] = history
+			result = append(result, history)
+		}
+		history.History = append(history.History, newClusterNodeDTO(node, checkInQuery.Result))
+	}
+
+	return Json(200, result)
+}
+
+// GET /api/admin/cluster/executions
+func GetAlertExecutions(c *middleware.Context) Response {
+	query := m.GetAlertExecutionsQuery{
+		OrgId:   c.QueryInt64("orgId"),
+		AlertId: c.QueryInt64("alertId"),
+		NodeId:  c.Query("nodeId"),
+		Limit:   c.QueryInt("limit"),
+	}
+	if err := bus.Dispatch(&query); err != nil {
+		return ApiError(500, "Failed to get alert executions", err)
+	}
+
+	return Json(200, query.Result)
+}
+
+// POST /api/admin/cluster/drain
+func DrainClusterNode(c *middleware.Context, dto dtos.DrainNodeCommand) Response {
+	cmd := clustering.DrainNodeCommand{Drain: dto.Drain}
+
+	if err := bus.Dispatch(&cmd); err != nil {
+		return ApiError(500, "Failed to drain node", err)
+	}
+
+	message := "node draining"
+	if !cmd.Drain {
+		message = "node stopped draining"
+	}
+
+	result := map[string]interface{}{
+		"nodeId":   cmd.ResultNodeId,
+		"draining": cmd.Drain,
+		"message":  message,
+	}
+
+	return Json(200, result)
+}
@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/components/null"
@@ -22,13 +23,72 @@ type AlertRule struct {
 	DashbboardUri  string           `json:"dashboardUri"`
 }
 
+// AlertDetail is an alert with the state of each series its queries returned.
+type AlertDetail struct {
+	*m.Alert
+	Instances []*m.AlertInstance `json:"instances"`
+}
+
+type AlertStateHistoryItem struct {
+	Id          int64            `json:"id"`
+	AlertId     int64            `json:"alertId"`
+	AlertName   string           `json:"alertName"`
+	DashboardId int64            `json:"dashboardId"`
+	PanelId     int64            `json:"panelId"`
+	NodeId      string           `json:"nodeId"`
+	PrevState   m.AlertStateType `json:"prevState"`
+	NewState    m.AlertStateType `json:"newState"`
+	Time        int64            `json:"time"`
+	Data        *simplejson.Json `json:"data"`
+}
+
+// AlertStateHistory is a page of state changes, newest first. NextCursor is
+// passed as before to get the next page and is 0 on the last one.
+type AlertStateHistory struct {
+	Items      []*AlertStateHistoryItem `json:"items"`
+	NextCursor int64                    `json:"nextCursor"`
+}
+
 type AlertNotification struct {
-	Id        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Type      string    `json:"type"`
-	IsDefault bool      `json:"isDefault"`
-	Created   time.Time `json:"created"`
-	Updated   time.Time `json:"updated"`
+	Id           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Type         string           `json:"type"`
+	IsDefault    bool             `json:"isDefault"`
+	SendReminder bool             `json:"sendReminder"`
+	Frequency    string           `json:"frequency"`
+	Settings     *simplejson.Json `json:"settings,omitempty"`
+	Created      time.Time        `json:"created"`
+	Updated      time.Time        `json:"updated"`
+}
+
+func NewAlertNotification(notification *m.AlertNotification) *AlertNotification {
+	return &AlertNotification{
+		Id:           notification.Id,
+		Name:         notification.Name,
+		Type:         notification.Type,
+		IsDefault:    notification.IsDefault,
+		SendReminder: notification.SendReminder,
+		Frequency:    formatFrequency(notification.Frequency),
+		Created:      notification.Created,
+		Updated:      notification.Updated,
+	}
+}
+
+// formatFrequency formats the frequency like it is entered, 15m rather
+// than 15m0s.
+func formatFrequency(frequency time.Duration) string {
+	if frequency <= 0 {
+		return ""
+	}
+
+	formatted := frequency.String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = formatted[:len(formatted)-2]
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = formatted[:len(formatted)-2]
+	}
+	return formatted
 }
 
 type AlertTestCommand struct {
@@ -36,6 +96,24 @@ type AlertTestCommand struct {
 	PanelId   int64            `json:"panelId" binding:"Required"`
 }
 
+// AlertBacktestCommand replays the stored alert AlertId, or the alert of
+// panel PanelId of Dashboard. From and To are epoch milliseconds or relative
+// to now like now-168h.
+type AlertBacktestCommand struct {
+	AlertId   int64            `json:"alertId"`
+	Dashboard *simplejson.Json `json:"dashboard"`
+	PanelId   int64            `json:"panelId"`
+	From      string           `json:"from"`
+	To        string           `json:"to"`
+}
+
+// AlertRenderTemplateCommand renders Template with the state of the alert
+// AlertId, or with sample data if it is not set.
+type AlertRenderTemplateCommand struct {
+	Template string `json:"template" binding:"Required"`
+	AlertId  int64  `json:"alertId"`
+}
+
 type AlertTestResult struct {
 	Firing         bool                  `json:"firing"`
 	State          m.AlertStateType      `json:"state"`
@@ -63,6 +141,10 @@ type NotificationTestCommand struct {
 	Settings *simplejson.Json `json:"settings"`
 }
 
+type SaveAlertRuleCommand struct {
+	Rule *simplejson.Json `json:"rule" binding:"Required"`
+}
+
 type PauseAlertCommand struct {
 	AlertId int64 `json:"alertId"`
 	Paused  bool  `json:"paused"`
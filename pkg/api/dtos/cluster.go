@@ -0,0 +1,27 @@
+package dtos
+
+type ClusterNode struct {
+	NodeId       string `json:"nodeId"`
+	PartId       int32  `json:"partId"`
+	AlertStatus  string `json:"alertStatus"`
+	AlertRunType string `json:"alertRunType"`
+	Heartbeat    int64  `json:"heartbeat"`
+	LastCheckIn  int64  `json:"lastCheckIn"`
+}
+
+type ClusterStatus struct {
+	Heartbeat         int64          `json:"heartbeat"`
+	NodeCount         int            `json:"nodeCount"`
+	Nodes             []*ClusterNode `json:"nodes"`
+	MissingAlertsNode *ClusterNode   `json:"missingAlertsNode"`
+	CleanupNode       *ClusterNode   `json:"cleanupNode"`
+}
+
+type ClusterNodeHistory struct {
+	NodeId  string         `json:"nodeId"`
+	History []*ClusterNode `json:"history"`
+}
+
+type DrainNodeCommand struct {
+	Drain bool `json:"drain"`
+}
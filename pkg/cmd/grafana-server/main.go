@@ -16,8 +16,10 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/clustering"
 	"github.com/grafana/grafana/pkg/services/sqlstore"
 	"github.com/grafana/grafana/pkg/setting"
 
@@ -140,8 +142,26 @@ func listenToSystemSignals(server models.GrafanaServer) {
 	case sig := <-signalChan:
 		// Stops trace if profiling has been enabled
 		trace.Stop()
+		if sig == syscall.SIGTERM {
+			drainClusterNode()
+		}
 		server.Shutdown(0, fmt.Sprintf("system signal: %s", sig))
 	case code = <-exitChan:
 		server.Shutdown(code, "startup error")
 	}
 }
+
+// drainClusterNode hands this node's alerts over to the rest of the cluster
+// and waits for the in-flight alert jobs before shutting down.
+func drainClusterNode() {
+	if !setting.ClusteringEnabled || !setting.AlertingEnabled || !setting.ExecuteAlerts {
+		return
+	}
+	cmd := &clustering.DrainNodeCommand{
+		Drain:   true,
+		Timeout: time.Duration(setting.ClusteringDrainTimeout) * time.Second,
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		log.Error(3, "Failed to drain cluster node: %v", err)
+	}
+}
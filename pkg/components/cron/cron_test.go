@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCronSchedule(t *testing.T) {
+	Convey("Test cron schedule", t, func() {
+		at := func(value string) time.Time {
+			t, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+			So(err, ShouldBeNil)
+			return t
+		}
+
+		Convey("Invalid expressions are refused", func() {
+			for _, spec := range []string{
+				"",
+				"* * * *",
+				"* * * * * *",
+				"60 * * * *",
+				"* 24 * * *",
+				"* * 0 * *",
+				"* * * 13 *",
+				"* * * * 8",
+				"*/0 * * * *",
+				"5-1 * * * *",
+				"a * * * *",
+				"@reboot",
+			} {
+				_, err := Parse(spec)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("Every ten minutes", func() {
+			s := MustParse("*/10 * * * *")
+			So(s.Matches(at("2017-04-26 10:20")), ShouldBeTrue)
+			So(s.Matches(at("2017-04-26 10:25")), ShouldBeFalse)
+			So(s.Next(at("2017-04-26 10:20")), ShouldResemble, at("2017-04-26 10:30"))
+			So(s.Next(at("2017-04-26 23:55")), ShouldResemble, at("2017-04-27 00:00"))
+		})
+
+		Convey("Lists, ranges and names", func() {
+			s := MustParse("0,30 9-17 * jan-mar mon-fri")
+			So(s.Matches(at("2017-03-01 09:30")), ShouldBeTrue)  // wednesday
+			So(s.Matches(at("2017-03-04 09:30")), ShouldBeFalse) // saturday
+			So(s.Matches(at("2017-04-03 09:30")), ShouldBeFalse) // april
+			So(s.Next(at("2017-03-03 17:30")), ShouldResemble, at("2017-03-06 09:00"))
+			So(s.Next(at("2017-03-31 17:30")), ShouldResemble, at("2018-01-01 09:00"))
+		})
+
+		Convey("Day of month or day of week", func() {
+			s := MustParse("0 0 13 * 5")
+			So(s.Matches(at("2017-01-13 00:00")), ShouldBeTrue) // friday the 13th
+			So(s.Matches(at("2017-02-13 00:00")), ShouldBeTrue) // monday
+			So(s.Matches(at("2017-02-17 00:00")), ShouldBeTrue) // friday
+			So(s.Matches(at("2017-02-14 00:00")), ShouldBeFalse)
+		})
+
+		Convey("Sunday is 0 or 7", func() {
+			So(MustParse("0 0 * * 7").Next(at("2017-04-26 00:00")), ShouldResemble, at("2017-04-30 00:00"))
+			So(MustParse("@weekly").Next(at("2017-04-26 00:00")), ShouldResemble, at("2017-04-30 00:00"))
+		})
+
+		Convey("Descriptors", func() {
+			So(MustParse("@daily").Next(at("2017-04-26 10:00")), ShouldResemble, at("2017-04-27 00:00"))
+			So(MustParse("@hourly").Next(at("2017-04-26 10:00")), ShouldResemble, at("2017-04-26 11:00"))
+			So(MustParse("@monthly").Next(at("2017-12-26 10:00")), ShouldResemble, at("2018-01-01 00:00"))
+		})
+
+		Convey("Schedule that never fires", func() {
+			So(MustParse("0 0 30 2 *").Next(at("2017-04-26 10:00")).IsZero(), ShouldBeTrue)
+		})
+	})
+}
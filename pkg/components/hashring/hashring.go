@@ -0,0 +1,110 @@
+package hashring
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// Ring is a consistent hashing ring. Every node is placed on the ring a
+// number of times (virtual nodes) so keys spread evenly and a membership
+// change only moves the keys owned by the node that joined or left.
+type Ring struct {
+	replicas int
+	hashes   []uint32
+	owners   map[uint32]string
+	nodes    map[string]bool
+}
+
+// New creates a ring with the given number of virtual nodes per node.
+func New(replicas int, nodes ...string) *Ring {
+	if replicas <= 0 {
+		replicas = 1
+	}
+	r := &Ring{
+		replicas: replicas,
+		owners:   make(map[uint32]string),
+		nodes:    make(map[string]bool),
+	}
+	r.Add(nodes...)
+	return r
+}
+
+// hashKey is FNV-1a followed by the murmur3 finalizer. FNV alone clusters
+// short sequential keys such as alert ids.
+func hashKey(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	hash := h.Sum32()
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	hash *= 0xc2b2ae35
+	hash ^= hash >> 16
+	return hash
+}
+
+// Add places the nodes on the ring. Nodes already on the ring are ignored.
+func (r *Ring) Add(nodes ...string) {
+	for _, node := range nodes {
+		if r.nodes[node] {
+			continue
+		}
+		r.nodes[node] = true
+		for i := 0; i < r.replicas; i++ {
+			hash := hashKey(node + "#" + strconv.Itoa(i))
+			// on a collision the lowest node id wins so every member builds the same ring
+			if owner, exists := r.owners[hash]; exists {
+				if owner < node {
+					continue
+				}
+			} else {
+				r.hashes = append(r.hashes, hash)
+			}
+			r.owners[hash] = node
+		}
+	}
+	sort.Sort(uint32Slice(r.hashes))
+}
+
+// Remove takes the node and its virtual nodes off the ring.
+func (r *Ring) Remove(node string) {
+	if !r.nodes[node] {
+		return
+	}
+	delete(r.nodes, node)
+	members := make([]string, 0, len(r.nodes))
+	for member := range r.nodes {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+
+	r.hashes = nil
+	r.owners = make(map[uint32]string)
+	r.nodes = make(map[string]bool)
+	r.Add(members...)
+}
+
+// Len returns the number of nodes on the ring.
+func (r *Ring) Len() int {
+	return len(r.nodes)
+}
+
+// Get returns the node owning the key, or an empty string if the ring is empty.
+func (r *Ring) Get(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+	return r.owners[r.hashes[idx]]
+}
+
+type uint32Slice []uint32
+
+func (s uint32Slice) Len() int           { return len(s) }
+func (s uint32Slice) Less(i, j int) bool { return s[i] < s[j] }
+func (s uint32Slice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
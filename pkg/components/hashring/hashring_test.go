@@ -0,0 +1,126 @@
+package hashring
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testReplicas = 200
+	testKeyCount = 10000
+)
+
+func testNodes(count int) []string {
+	nodes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		nodes = append(nodes, fmt.Sprintf("grafana-%d:3000", i))
+	}
+	return nodes
+}
+
+func assignments(r *Ring) map[string]string {
+	result := make(map[string]string, testKeyCount)
+	for i := 1; i <= testKeyCount; i++ {
+		key := strconv.Itoa(i)
+		result[key] = r.Get(key)
+	}
+	return result
+}
+
+func moved(before, after map[string]string) int {
+	count := 0
+	for key, owner := range before {
+		if after[key] != owner {
+			count++
+		}
+	}
+	return count
+}
+
+func TestHashRing(t *testing.T) {
+	Convey("Given a consistent hash ring", t, func() {
+
+		Convey("Empty ring owns nothing", func() {
+			r := New(testReplicas)
+			So(r.Get("1"), ShouldEqual, "")
+			So(r.Len(), ShouldEqual, 0)
+		})
+
+		Convey("Ring is independent of the order nodes are added", func() {
+			nodes := testNodes(5)
+			r1 := New(testReplicas, nodes...)
+			r2 := New(testReplicas, nodes[4], nodes[2], nodes[0], nodes[3], nodes[1])
+			So(moved(assignments(r1), assignments(r2)), ShouldEqual, 0)
+		})
+
+		Convey("Keys are spread evenly between nodes", func() {
+			r := New(testReplicas, testNodes(5)...)
+			counts := make(map[string]int)
+			for _, owner := range assignments(r) {
+				counts[owner]++
+			}
+			So(len(counts), ShouldEqual, 5)
+			for _, count := range counts {
+				So(count, ShouldBeBetween, testKeyCount/5*70/100, testKeyCount/5*130/100)
+			}
+		})
+
+		Convey("Node joining only takes over about 1/N of the keys", func() {
+			for nodeCount := 2; nodeCount <= 10; nodeCount++ {
+				nodes := testNodes(nodeCount + 1)
+				r := New(testReplicas, nodes[:nodeCount]...)
+				before := assignments(r)
+
+				r.Add(nodes[nodeCount])
+				after := assignments(r)
+
+				for key, owner := range after {
+					if before[key] != owner {
+						So(owner, ShouldEqual, nodes[nodeCount])
+					}
+				}
+				expected := testKeyCount / (nodeCount + 1)
+				So(moved(before, after), ShouldBeBetween, expected*60/100, expected*140/100)
+			}
+		})
+
+		Convey("Node leaving only gives away its own keys", func() {
+			for nodeCount := 3; nodeCount <= 10; nodeCount++ {
+				nodes := testNodes(nodeCount)
+				r := New(testReplicas, nodes...)
+				before := assignments(r)
+
+				r.Remove(nodes[1])
+				after := assignments(r)
+
+				for key, owner := range before {
+					if owner != nodes[1] {
+						So(after[key], ShouldEqual, owner)
+					}
+				}
+				expected := testKeyCount / nodeCount
+				So(moved(before, after), ShouldBeBetween, expected*60/100, expected*140/100)
+			}
+		})
+
+		Convey("Churn moves far fewer keys than modulo partitioning", func() {
+			nodes := testNodes(6)
+			r := New(testReplicas, nodes[:5]...)
+			before := assignments(r)
+			r.Add(nodes[5])
+			ringMoved := moved(before, assignments(r))
+
+			moduloMoved := 0
+			for i := 1; i <= testKeyCount; i++ {
+				if i%5 != i%6 {
+					moduloMoved++
+				}
+			}
+
+			So(ringMoved, ShouldBeLessThan, moduloMoved/3)
+		})
+	})
+}
@@ -1,6 +1,10 @@
 package metrics
 
-import "github.com/grafana/grafana/pkg/log"
+import (
+	"sort"
+
+	"github.com/grafana/grafana/pkg/log"
+)
 
 type MetricMeta struct {
 	tags map[string]string
@@ -45,9 +49,16 @@ func (m *MetricMeta) StringifyTags() string {
 		return ""
 	}
 
+	// sorted so a metric with several tags keeps the same path
+	keys := make([]string, 0, len(m.tags))
+	for key := range m.tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	str := ""
-	for key, value := range m.tags {
-		str += "." + key + "_" + value
+	for _, key := range keys {
+		str += "." + key + "_" + m.tags[key]
 	}
 
 	return str
@@ -1,6 +1,12 @@
 package metrics
 
-import "sync/atomic"
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/grafana/pkg/log"
+)
 
 // Counters hold an int64 value that can be incremented and decremented.
 type Counter interface {
@@ -26,6 +32,51 @@ func RegCounter(name string, tagStrings ...string) Counter {
 	return cr
 }
 
+// CounterSet is a set of counters of one metric that differ in the values of
+// some of their tags, e.g. the partition a job ran for. A counter is
+// registered the first time its tag values are used.
+type CounterSet struct {
+	name       string
+	tagKeys    []string
+	tagStrings []string
+
+	lock     sync.Mutex
+	counters map[string]Counter
+}
+
+func RegCounterSet(name string, tagKeys []string, tagStrings ...string) *CounterSet {
+	return &CounterSet{
+		name:       name,
+		tagKeys:    tagKeys,
+		tagStrings: tagStrings,
+		counters:   make(map[string]Counter),
+	}
+}
+
+// With returns the counter for the given values of the set's tag keys.
+func (s *CounterSet) With(tagValues ...string) Counter {
+	if len(tagValues) != len(s.tagKeys) {
+		log.Fatal(3, "Metrics: expected values for tags %v, got %v", s.tagKeys, tagValues)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := strings.Join(tagValues, "/")
+	if counter, ok := s.counters[key]; ok {
+		return counter
+	}
+
+	tagStrings := make([]string, 0, len(s.tagStrings)+2*len(s.tagKeys))
+	tagStrings = append(tagStrings, s.tagStrings...)
+	for i, tagKey := range s.tagKeys {
+		tagStrings = append(tagStrings, tagKey, tagValues[i])
+	}
+	counter := RegCounter(s.name, tagStrings...)
+	s.counters[key] = counter
+	return counter
+}
+
 // StandardCounter is the standard implementation of a Counter and uses the
 // sync/atomic package to manage a single int64 value.
 type StandardCounter struct {
@@ -75,3 +75,29 @@ func TestGraphitePublisher(t *testing.T) {
 		So(publisher, ShouldBeNil)
 	})
 }
+
+func TestMetricTags(t *testing.T) {
+	Convey("Test metric tags are stringified in a stable order", t, func() {
+		meta := NewMetricMeta("clustering.jobs", []string{"result", "scheduled", "node", "grafana-1_3000"})
+		for i := 0; i < 10; i++ {
+			So(meta.StringifyTags(), ShouldEqual, ".node_grafana-1_3000.result_scheduled")
+		}
+	})
+
+	Convey("Test counter sets register a counter per tag value", t, func() {
+		jobs := RegCounterSet("clustering.jobs", []string{"partition"}, "result", "scheduled")
+		jobs.With("0").Inc(1)
+		jobs.With("0").Inc(1)
+		jobs.With("1").Inc(1)
+
+		So(jobs.With("0").Count(), ShouldEqual, 2)
+		So(jobs.With("1").Count(), ShouldEqual, 1)
+		So(jobs.With("1").(*StandardCounter).StringifyTags(), ShouldEqual, ".partition_1.result_scheduled")
+	})
+
+	Convey("Test cluster node tag is safe for graphite", t, func() {
+		setting.InstanceName = "hostname.with.dots.com"
+		setting.HttpPort = "3000"
+		So(clusterNodeTag(), ShouldEqual, "hostname_with_dots_com_3000")
+	})
+}
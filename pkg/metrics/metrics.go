@@ -1,5 +1,12 @@
 package metrics
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
 var MetricStats Registry
 var UseNilMetrics bool
 
@@ -9,52 +16,56 @@ func init() {
 }
 
 var (
-	M_Instance_Start                       Counter
-	M_Page_Status_200                      Counter
-	M_Page_Status_500                      Counter
-	M_Page_Status_404                      Counter
-	M_Page_Status_Unknown                  Counter
-	M_Api_Status_200                       Counter
-	M_Api_Status_404                       Counter
-	M_Api_Status_500                       Counter
-	M_Api_Status_Unknown                   Counter
-	M_Proxy_Status_200                     Counter
-	M_Proxy_Status_404                     Counter
-	M_Proxy_Status_500                     Counter
-	M_Proxy_Status_Unknown                 Counter
-	M_Api_User_SignUpStarted               Counter
-	M_Api_User_SignUpCompleted             Counter
-	M_Api_User_SignUpInvite                Counter
-	M_Api_Dashboard_Save                   Timer
-	M_Api_Dashboard_Get                    Timer
-	M_Api_Dashboard_Search                 Timer
-	M_Api_Admin_User_Create                Counter
-	M_Api_Login_Post                       Counter
-	M_Api_Login_OAuth                      Counter
-	M_Api_Org_Create                       Counter
-	M_Api_Dashboard_Snapshot_Create        Counter
-	M_Api_Dashboard_Snapshot_External      Counter
-	M_Api_Dashboard_Snapshot_Get           Counter
-	M_Models_Dashboard_Insert              Counter
-	M_Alerting_Result_State_Alerting       Counter
-	M_Alerting_Result_State_Ok             Counter
-	M_Alerting_Result_State_Paused         Counter
-	M_Alerting_Result_State_NoData         Counter
-	M_Alerting_Result_State_Pending        Counter
-	M_Alerting_Notification_Sent_Slack     Counter
-	M_Alerting_Notification_Sent_Email     Counter
-	M_Alerting_Notification_Sent_Webhook   Counter
-	M_Alerting_Notification_Sent_DingDing  Counter
-	M_Alerting_Notification_Sent_PagerDuty Counter
-	M_Alerting_Notification_Sent_LINE      Counter
-	M_Alerting_Notification_Sent_Victorops Counter
-	M_Alerting_Notification_Sent_OpsGenie  Counter
-	M_Alerting_Notification_Sent_Telegram  Counter
-	M_Alerting_Notification_Sent_Threema   Counter
-	M_Alerting_Notification_Sent_Sensu     Counter
-	M_Alerting_Notification_Sent_Pushover  Counter
-	M_Aws_CloudWatch_GetMetricStatistics   Counter
-	M_Aws_CloudWatch_ListMetrics           Counter
+	M_Instance_Start                          Counter
+	M_Page_Status_200                         Counter
+	M_Page_Status_500                         Counter
+	M_Page_Status_404                         Counter
+	M_Page_Status_Unknown                     Counter
+	M_Api_Status_200                          Counter
+	M_Api_Status_404                          Counter
+	M_Api_Status_500                          Counter
+	M_Api_Status_Unknown                      Counter
+	M_Proxy_Status_200                        Counter
+	M_Proxy_Status_404                        Counter
+	M_Proxy_Status_500                        Counter
+	M_Proxy_Status_Unknown                    Counter
+	M_Api_User_SignUpStarted                  Counter
+	M_Api_User_SignUpCompleted                Counter
+	M_Api_User_SignUpInvite                   Counter
+	M_Api_Dashboard_Save                      Timer
+	M_Api_Dashboard_Get                       Timer
+	M_Api_Dashboard_Search                    Timer
+	M_Api_Admin_User_Create                   Counter
+	M_Api_Login_Post                          Counter
+	M_Api_Login_OAuth                         Counter
+	M_Api_Org_Create                          Counter
+	M_Api_Dashboard_Snapshot_Create           Counter
+	M_Api_Dashboard_Snapshot_External         Counter
+	M_Api_Dashboard_Snapshot_Get              Counter
+	M_Models_Dashboard_Insert                 Counter
+	M_Alerting_Result_State_Alerting          Counter
+	M_Alerting_Result_State_Ok                Counter
+	M_Alerting_Result_State_Paused            Counter
+	M_Alerting_Result_State_NoData            Counter
+	M_Alerting_Result_State_Pending           Counter
+	M_Alerting_Notification_Sent_Slack        Counter
+	M_Alerting_Notification_Sent_Email        Counter
+	M_Alerting_Notification_Sent_Webhook      Counter
+	M_Alerting_Notification_Sent_DingDing     Counter
+	M_Alerting_Notification_Sent_PagerDuty    Counter
+	M_Alerting_Notification_Sent_LINE         Counter
+	M_Alerting_Notification_Sent_Victorops    Counter
+	M_Alerting_Notification_Sent_OpsGenie     Counter
+	M_Alerting_Notification_Sent_Telegram     Counter
+	M_Alerting_Notification_Sent_Threema      Counter
+	M_Alerting_Notification_Sent_Sensu        Counter
+	M_Alerting_Notification_Sent_Pushover     Counter
+	M_Alerting_Notification_Sent_Teams        Counter
+	M_Alerting_Notification_Sent_GoogleChat   Counter
+	M_Alerting_Notification_Sent_Discord      Counter
+	M_Alerting_Notification_Sent_Alertmanager Counter
+	M_Aws_CloudWatch_GetMetricStatistics      Counter
+	M_Aws_CloudWatch_ListMetrics              Counter
 
 	// Timers
 	M_DataSource_ProxyReq_Timer Timer
@@ -71,6 +82,18 @@ var (
 	M_Clustering_Active_Nodes         Gauge
 	M_Clustering_Pending_Alert_Jobs   Gauge
 	M_Clustering_Missing_Alerts_Count Gauge
+	M_Clustering_Next_Cleanup         Gauge
+	M_Clustering_Next_Missing_Alerts  Gauge
+	M_Clustering_Next_HB_Retention    Gauge
+
+	// Clustering, per node
+	M_Clustering_Scheduled_Jobs     *CounterSet
+	M_Clustering_Executed_Jobs      *CounterSet
+	M_Clustering_Failed_Jobs        *CounterSet
+	M_Clustering_Dispatcher_Latency Timer
+	M_Clustering_State_Transitions  *CounterSet
+	M_Clustering_Rejected_CheckIns  Counter
+	M_Clustering_Stale_Tasks        Counter
 )
 
 func initMetricVars(settings *MetricSettings) {
@@ -131,6 +154,10 @@ func initMetricVars(settings *MetricSettings) {
 	M_Alerting_Notification_Sent_Sensu = RegCounter("alerting.notifications_sent", "type", "sensu")
 	M_Alerting_Notification_Sent_LINE = RegCounter("alerting.notifications_sent", "type", "LINE")
 	M_Alerting_Notification_Sent_Pushover = RegCounter("alerting.notifications_sent", "type", "pushover")
+	M_Alerting_Notification_Sent_Teams = RegCounter("alerting.notifications_sent", "type", "teams")
+	M_Alerting_Notification_Sent_GoogleChat = RegCounter("alerting.notifications_sent", "type", "googlechat")
+	M_Alerting_Notification_Sent_Discord = RegCounter("alerting.notifications_sent", "type", "discord")
+	M_Alerting_Notification_Sent_Alertmanager = RegCounter("alerting.notifications_sent", "type", "alertmanager")
 
 	M_Aws_CloudWatch_GetMetricStatistics = RegCounter("aws.cloudwatch.get_metric_statistics")
 	M_Aws_CloudWatch_ListMetrics = RegCounter("aws.cloudwatch.list_metrics")
@@ -147,7 +174,24 @@ func initMetricVars(settings *MetricSettings) {
 	M_StatTotal_Playlists = RegGauge("stat_totals", "stat", "playlists")
 
 	//Clustering
-	M_Clustering_Active_Nodes = RegGauge("clustering.active_nodes")
-	M_Clustering_Pending_Alert_Jobs = RegGauge("clustering.pending_alert_jobs")
-	M_Clustering_Missing_Alerts_Count = RegGauge("clustering.missing_alerts_count")
+	node := clusterNodeTag()
+	M_Clustering_Active_Nodes = RegGauge("clustering.active_nodes", "node", node)
+	M_Clustering_Pending_Alert_Jobs = RegGauge("clustering.pending_alert_jobs", "node", node)
+	M_Clustering_Missing_Alerts_Count = RegGauge("clustering.missing_alerts_count", "node", node)
+	M_Clustering_Next_Cleanup = RegGauge("clustering.next_run", "job", "cleanup", "node", node)
+	M_Clustering_Next_Missing_Alerts = RegGauge("clustering.next_run", "job", "missing_alerts", "node", node)
+	M_Clustering_Next_HB_Retention = RegGauge("clustering.next_run", "job", "hb_retention", "node", node)
+	M_Clustering_Scheduled_Jobs = RegCounterSet("clustering.jobs", []string{"partition"}, "result", "scheduled", "node", node)
+	M_Clustering_Executed_Jobs = RegCounterSet("clustering.jobs", []string{"partition"}, "result", "executed", "node", node)
+	M_Clustering_Failed_Jobs = RegCounterSet("clustering.jobs", []string{"partition"}, "result", "failed", "node", node)
+	M_Clustering_Dispatcher_Latency = RegTimer("clustering.dispatcher_task_latency", "node", node)
+	M_Clustering_State_Transitions = RegCounterSet("clustering.state_transitions", []string{"from", "to"}, "node", node)
+	M_Clustering_Rejected_CheckIns = RegCounter("clustering.rejected_checkins", "node", node)
+	M_Clustering_Stale_Tasks = RegCounter("clustering.stale_tasks", "node", node)
+}
+
+// clusterNodeTag is the cluster node id (instance name and http port) made
+// safe to use in a Graphite metric path.
+func clusterNodeTag() string {
+	return strings.NewReplacer(".", "_", ":", "_").Replace(fmt.Sprintf("%v:%v", setting.InstanceName, setting.HttpPort))
 }
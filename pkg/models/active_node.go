@@ -1,5 +1,9 @@
 package models
 
+import "errors"
+
+var ErrStaleFencingToken = errors.New("Fencing token is stale")
+
 //ActiveNode model
 type ActiveNode struct {
 	Id           int64  `json:"id"`
@@ -18,6 +22,7 @@ const (
 	CLN_ALERT_STATUS_READY      = "ready"
 	CLN_ALERT_STATUS_PROCESSING = "processing"
 	CLN_ALERT_STATUS_SCHEDULING = "scheduling"
+	CLN_ALERT_STATUS_DRAINING   = "draining"
 )
 
 type GetActiveNodeByIdHeartbeatQuery struct {
@@ -47,6 +52,13 @@ type GetLastDBTimeIntervalQuery struct {
 	Result int64
 }
 
+// ValidateFencingTokenQuery fails with ErrStaleFencingToken if Token, the
+// heartbeat interval a node scheduled work for, is older than the current
+// heartbeat interval of the database clock.
+type ValidateFencingTokenQuery struct {
+	Token int64
+}
+
 type GetActiveNodesCountCommand struct {
 	NodeId    string
 	Heartbeat int64
@@ -57,10 +69,39 @@ type GetNodeProcessingMissingAlertsCommand struct {
 	Result *ActiveNode
 }
 
+// ClusteringCleanupCommand removes heartbeats, alert executions, cluster
+// notifications, annotations and alert state history that are older than their
+// retention period. The state history shares the annotation retention, the
+// rest the heartbeat retention. Either part can be skipped.
+// FencingToken is the heartbeat the cleanup was scheduled for, the cleanup is
+// refused once it is stale. 0 skips the check.
 type ClusteringCleanupCommand struct {
-	LastHeartbeat int64
+	LastHeartbeat   int64
+	FencingToken    int64
+	SkipHeartbeats  bool
+	SkipAnnotations bool
 }
 type ClusteringCleanupCheckCommand struct {
 	LastHeartbeat int64
 	Result        bool
 }
+
+type GetLatestActiveNodesQuery struct {
+	Heartbeat int64
+	Result    []*ActiveNode
+}
+
+type GetActiveNodeHistoryQuery struct {
+	NodeId     string
+	Heartbeats int
+	Result     []*ActiveNode
+}
+
+type GetActiveNodesLastCheckInQuery struct {
+	Result map[string]int64
+}
+
+type GetLastNodeForRunTypeQuery struct {
+	AlertRunType string
+	Result       *ActiveNode
+}
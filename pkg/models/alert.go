@@ -85,9 +85,18 @@ type Alert struct {
 }
 
 func (alert *Alert) ValidToSave() bool {
+	if alert.IsStandalone() {
+		return alert.OrgId != 0 && alert.Name != ""
+	}
 	return alert.DashboardId != 0 && alert.OrgId != 0 && alert.PanelId != 0
 }
 
+// IsStandalone reports whether the alert is an alert rule of its own rather
+// than the alert of a dashboard panel.
+func (alert *Alert) IsStandalone() bool {
+	return alert.DashboardId == 0 && alert.PanelId == 0
+}
+
 func (alert *Alert) ShouldUpdateState(newState AlertStateType) bool {
 	return alert.State != newState
 }
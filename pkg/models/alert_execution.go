@@ -0,0 +1,64 @@
+package models
+
+import "errors"
+
+var ErrAlertExecutionClaimed = errors.New("Alert execution is already claimed")
+
+const (
+	ALERT_EXECUTION_OUTCOME_RUNNING   = "running"
+	ALERT_EXECUTION_OUTCOME_ERROR     = "error"
+	ALERT_EXECUTION_OUTCOME_TIMEOUT   = "timeout"
+	ALERT_EXECUTION_OUTCOME_CANCELLED = "cancelled"
+)
+
+// AlertExecution is a ledger entry for one scheduled evaluation of an alert.
+// A scheduled interval can be claimed by one node only. The outcome is the
+// resulting alert state, or running/error/timeout/cancelled.
+type AlertExecution struct {
+	Id          int64  `json:"id"`
+	AlertId     int64  `json:"alertId"`
+	OrgId       int64  `json:"orgId"`
+	ScheduledAt int64  `json:"scheduledAt"`
+	NodeId      string `json:"nodeId"`
+	StartedAt   int64  `json:"startedAt"`
+	FinishedAt  int64  `json:"finishedAt"`
+	DurationMs  int64  `json:"durationMs"`
+	Outcome     string `json:"outcome"`
+	Error       string `json:"error"`
+}
+
+// ClaimAlertExecutionCommand inserts the ledger entry for the alert and
+// scheduled interval unless it exists. It returns ErrAlertExecutionClaimed if
+// the interval was claimed before.
+type ClaimAlertExecutionCommand struct {
+	AlertId     int64
+	OrgId       int64
+	ScheduledAt int64
+	NodeId      string
+	StartedAt   int64
+
+	Result *AlertExecution
+}
+
+type FinishAlertExecutionCommand struct {
+	Id         int64
+	FinishedAt int64
+	DurationMs int64
+	Outcome    string
+	Error      string
+}
+
+type GetAlertExecutionsQuery struct {
+	OrgId   int64
+	AlertId int64
+	NodeId  string
+	Limit   int
+
+	Result []*AlertExecution
+}
+
+// GetLastAlertExecutionsQuery returns the last scheduled interval that was
+// claimed for each alert.
+type GetLastAlertExecutionsQuery struct {
+	Result map[int64]int64
+}
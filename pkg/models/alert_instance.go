@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// AlertInstance is the state of one series of an alert. The series returned
+// by the alert queries are told apart by their metric name and tags, hashed
+// into InstanceKey.
+type AlertInstance struct {
+	Id           int64             `json:"id"`
+	OrgId        int64             `json:"-"`
+	AlertId      int64             `json:"alertId"`
+	InstanceKey  string            `json:"key"`
+	Metric       string            `json:"metric"`
+	Tags         map[string]string `json:"tags"`
+	State        AlertStateType    `json:"state"`
+	NewStateDate time.Time         `json:"newStateDate"`
+	Updated      time.Time         `json:"updated"`
+}
+
+type GetAlertInstancesQuery struct {
+	OrgId   int64
+	AlertId int64
+
+	Result []*AlertInstance
+}
+
+// SaveAlertInstancesCommand inserts the instances of the alert, or updates
+// the state of the ones with the same instance key.
+type SaveAlertInstancesCommand struct {
+	OrgId     int64
+	AlertId   int64
+	Instances []*AlertInstance
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const DEFAULT_ALERT_NOTIFICATION_LOG_LIMIT = 100
+
+var ErrAlertNotificationLogNotFound = errors.New("Alert notification log entry not found")
+
+// AlertNotificationLog is a notification that could not be delivered after
+// all attempts. Payload is the state of the alert the notification was built
+// from, so it can be sent again.
+type AlertNotificationLog struct {
+	Id         int64            `json:"id"`
+	OrgId      int64            `json:"-"`
+	AlertId    int64            `json:"alertId"`
+	NotifierId int64            `json:"notifierId"`
+	Payload    *simplejson.Json `json:"payload"`
+	Error      string           `json:"error"`
+	Attempts   int              `json:"attempts"`
+	Created    time.Time        `json:"created"`
+	Replayed   bool             `json:"replayed"`
+	ReplayedAt time.Time        `json:"replayedAt"`
+}
+
+type SaveAlertNotificationLogCommand struct {
+	OrgId      int64
+	AlertId    int64
+	NotifierId int64
+	Payload    *simplejson.Json
+	Error      string
+	Attempts   int
+
+	Result *AlertNotificationLog
+}
+
+// MarkAlertNotificationLogReplayedCommand records that the notification was
+// delivered when it was sent again.
+type MarkAlertNotificationLogReplayedCommand struct {
+	Id         int64
+	OrgId      int64
+	ReplayedAt time.Time
+}
+
+// GetAlertNotificationLogQuery returns the newest entries first. Replayed
+// entries are left out unless IncludeReplayed is set.
+type GetAlertNotificationLogQuery struct {
+	OrgId           int64
+	AlertId         int64
+	NotifierId      int64
+	IncludeReplayed bool
+	Limit           int
+
+	Result []*AlertNotificationLog
+}
+
+type GetAlertNotificationLogByIdQuery struct {
+	Id    int64
+	OrgId int64
+
+	Result *AlertNotificationLog
+}
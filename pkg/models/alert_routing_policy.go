@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrAlertRoutingPolicyNotFound       = errors.New("Alert routing policy not found")
+	ErrAlertRoutingPolicyMissingTarget  = errors.New("Alert routing policy needs at least one notification id")
+	ErrAlertRoutingPolicyInvalidPattern = errors.New("Alert routing policy rule name pattern is not a valid regular expression")
+)
+
+// AlertRoutingPolicy sends the notifications of the alert rules it matches
+// to NotificationIds. All matchers that are set have to match, a policy
+// without matchers matches every rule. Policies are tried in the order of
+// Position, and the first one that matches ends the routing unless it has
+// Continue set.
+type AlertRoutingPolicy struct {
+	Id              int64             `json:"id"`
+	OrgId           int64             `json:"-"`
+	Name            string            `json:"name"`
+	Position        int64             `json:"position"`
+	RulePattern     string            `json:"rulePattern"`
+	DashboardTag    string            `json:"dashboardTag"`
+	Severity        string            `json:"severity"`
+	Labels          map[string]string `json:"labels"`
+	NotificationIds []int64           `json:"notificationIds"`
+	Continue        bool              `json:"continue"`
+	Created         time.Time         `json:"created"`
+	Updated         time.Time         `json:"updated"`
+}
+
+func (p *AlertRoutingPolicy) Validate() error {
+	if len(p.NotificationIds) == 0 {
+		return ErrAlertRoutingPolicyMissingTarget
+	}
+
+	if p.RulePattern != "" {
+		if _, err := regexp.Compile(p.RulePattern); err != nil {
+			return ErrAlertRoutingPolicyInvalidPattern
+		}
+	}
+
+	return nil
+}
+
+// Matches reports whether the policy applies to the alert rule.
+func (p *AlertRoutingPolicy) Matches(ruleName, severity string, labels map[string]string, dashboardTags []string) bool {
+	if p.Severity != "" && p.Severity != severity {
+		return false
+	}
+
+	for key, value := range p.Labels {
+		if labelValue, exists := labels[key]; !exists || labelValue != value {
+			return false
+		}
+	}
+
+	if p.RulePattern != "" {
+		pattern, err := regexp.Compile(p.RulePattern)
+		if err != nil || !pattern.MatchString(ruleName) {
+			return false
+		}
+	}
+
+	if p.DashboardTag != "" {
+		for _, tag := range dashboardTags {
+			if tag == p.DashboardTag {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+type SaveAlertRoutingPolicyCommand struct {
+	Name            string            `json:"name" binding:"Required"`
+	Position        int64             `json:"position"`
+	RulePattern     string            `json:"rulePattern"`
+	DashboardTag    string            `json:"dashboardTag"`
+	Severity        string            `json:"severity"`
+	Labels          map[string]string `json:"labels"`
+	NotificationIds []int64           `json:"notificationIds"`
+	Continue        bool              `json:"continue"`
+
+	Id     int64 `json:"-"`
+	OrgId  int64 `json:"-"`
+	Result *AlertRoutingPolicy
+}
+
+type DeleteAlertRoutingPolicyCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+type GetAlertRoutingPoliciesQuery struct {
+	OrgId int64
+
+	Result []*AlertRoutingPolicy
+}
+
+type GetAlertRoutingPolicyByIdQuery struct {
+	Id    int64
+	OrgId int64
+
+	Result *AlertRoutingPolicy
+}
+
+// GetRoutedNotificationIdsQuery returns the notification ids that the
+// routing policies of the org send the notifications of the alert rule to.
+type GetRoutedNotificationIdsQuery struct {
+	OrgId       int64
+	DashboardId int64
+	RuleName    string
+	Severity    string
+	Labels      map[string]string
+
+	Result []int64
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRoutingPolicy(t *testing.T) {
+	Convey("Testing alert routing policies", t, func() {
+		labels := map[string]string{"team": "db", "env": "prod"}
+
+		Convey("Policy without matchers matches every rule", func() {
+			policy := &AlertRoutingPolicy{NotificationIds: []int64{1}}
+			So(policy.Validate(), ShouldBeNil)
+			So(policy.Matches("cpu", "", nil, nil), ShouldBeTrue)
+		})
+
+		Convey("All matchers have to match", func() {
+			policy := &AlertRoutingPolicy{
+				RulePattern:  "^db ",
+				DashboardTag: "database",
+				Severity:     "critical",
+				Labels:       map[string]string{"team": "db"},
+			}
+			So(policy.Matches("db replication lag", "critical", labels, []string{"database"}), ShouldBeTrue)
+			So(policy.Matches("db replication lag", "warning", labels, []string{"database"}), ShouldBeFalse)
+			So(policy.Matches("db replication lag", "critical", map[string]string{"team": "web"}, []string{"database"}), ShouldBeFalse)
+			So(policy.Matches("db replication lag", "critical", labels, []string{"web"}), ShouldBeFalse)
+			So(policy.Matches("cpu", "critical", labels, []string{"database"}), ShouldBeFalse)
+		})
+
+		Convey("Policy needs a notification and a valid pattern", func() {
+			So((&AlertRoutingPolicy{}).Validate(), ShouldEqual, ErrAlertRoutingPolicyMissingTarget)
+			So((&AlertRoutingPolicy{RulePattern: "(", NotificationIds: []int64{1}}).Validate(), ShouldEqual, ErrAlertRoutingPolicyInvalidPattern)
+		})
+	})
+}
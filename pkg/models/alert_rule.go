@@ -0,0 +1,34 @@
+package models
+
+import "errors"
+
+var ErrAlertRuleNotFound = errors.New("Alert rule not found")
+
+// SaveAlertRuleCommand inserts the standalone alert rule, or updates it if
+// the Id of the alert is set.
+type SaveAlertRuleCommand struct {
+	OrgId  int64
+	UserId int64
+	Alert  *Alert
+
+	Result *Alert
+}
+
+type DeleteAlertRuleCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+// GetAlertRulesQuery returns the standalone alert rules of the org.
+type GetAlertRulesQuery struct {
+	OrgId int64
+
+	Result []*Alert
+}
+
+type GetAlertRuleByIdQuery struct {
+	Id    int64
+	OrgId int64
+
+	Result *Alert
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+const (
+	ALERT_SILENCE_RECURRENCE_DAILY  = "daily"
+	ALERT_SILENCE_RECURRENCE_WEEKLY = "weekly"
+)
+
+var (
+	ErrAlertSilenceNotFound       = errors.New("Alert silence not found")
+	ErrAlertSilenceMissingMatcher = errors.New("Alert silence needs an alert id, dashboard id, dashboard tag or rule name pattern")
+	ErrAlertSilenceInvalidPattern = errors.New("Alert silence rule name pattern is not a valid regular expression")
+	ErrAlertSilenceInvalidWindow  = errors.New("Alert silence must end after it starts, and recurring windows must be shorter than their recurrence")
+)
+
+// AlertSilence suppresses the notifications of the alerts it matches while
+// the alerts keep being evaluated. All matchers that are set have to match.
+// A silence with a recurrence is a maintenance window that repeats the
+// window from StartsAt to EndsAt every day or week until it expires.
+type AlertSilence struct {
+	Id           int64     `json:"id"`
+	OrgId        int64     `json:"-"`
+	AlertId      int64     `json:"alertId"`
+	DashboardId  int64     `json:"dashboardId"`
+	DashboardTag string    `json:"dashboardTag"`
+	RulePattern  string    `json:"rulePattern"`
+	Comment      string    `json:"comment"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt"`
+	Recurrence   string    `json:"recurrence"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+	CreatedBy    int64     `json:"createdBy"`
+	Created      time.Time `json:"created"`
+	Updated      time.Time `json:"updated"`
+}
+
+func (s *AlertSilence) recurrencePeriod() time.Duration {
+	switch s.Recurrence {
+	case ALERT_SILENCE_RECURRENCE_DAILY:
+		return 24 * time.Hour
+	case ALERT_SILENCE_RECURRENCE_WEEKLY:
+		return 7 * 24 * time.Hour
+	}
+	return 0
+}
+
+func (s *AlertSilence) Validate() error {
+	if s.AlertId == 0 && s.DashboardId == 0 && s.DashboardTag == "" && s.RulePattern == "" {
+		return ErrAlertSilenceMissingMatcher
+	}
+
+	if s.RulePattern != "" {
+		if _, err := regexp.Compile(s.RulePattern); err != nil {
+			return ErrAlertSilenceInvalidPattern
+		}
+	}
+
+	window := s.EndsAt.Sub(s.StartsAt)
+	if window <= 0 {
+		return ErrAlertSilenceInvalidWindow
+	}
+
+	period := s.recurrencePeriod()
+	if s.Recurrence != "" && (period == 0 || window >= period) {
+		return ErrAlertSilenceInvalidWindow
+	}
+
+	return nil
+}
+
+// IsActive reports whether now is within a window of the silence.
+func (s *AlertSilence) IsActive(now time.Time) bool {
+	if !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt) {
+		return false
+	}
+	if now.Before(s.StartsAt) {
+		return false
+	}
+
+	period := s.recurrencePeriod()
+	if period == 0 {
+		return now.Before(s.EndsAt)
+	}
+
+	offset := now.Sub(s.StartsAt) % period
+	return offset < s.EndsAt.Sub(s.StartsAt)
+}
+
+// Matches reports whether the silence applies to the alert.
+func (s *AlertSilence) Matches(alertId, dashboardId int64, ruleName string, dashboardTags []string) bool {
+	if s.AlertId != 0 && s.AlertId != alertId {
+		return false
+	}
+
+	if s.DashboardId != 0 && s.DashboardId != dashboardId {
+		return false
+	}
+
+	if s.RulePattern != "" {
+		pattern, err := regexp.Compile(s.RulePattern)
+		if err != nil || !pattern.MatchString(ruleName) {
+			return false
+		}
+	}
+
+	if s.DashboardTag != "" {
+		for _, tag := range dashboardTags {
+			if tag == s.DashboardTag {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+type CreateAlertSilenceCommand struct {
+	AlertId      int64     `json:"alertId"`
+	DashboardId  int64     `json:"dashboardId"`
+	DashboardTag string    `json:"dashboardTag"`
+	RulePattern  string    `json:"rulePattern"`
+	Comment      string    `json:"comment"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt" binding:"Required"`
+	Recurrence   string    `json:"recurrence"`
+
+	OrgId  int64 `json:"-"`
+	UserId int64 `json:"-"`
+	Result *AlertSilence
+}
+
+// ExpireAlertSilenceCommand ends the silence, including the windows of a
+// recurring silence that are still to come.
+type ExpireAlertSilenceCommand struct {
+	Id    int64
+	OrgId int64
+
+	Result *AlertSilence
+}
+
+type GetAlertSilencesQuery struct {
+	OrgId  int64
+	Active bool // only silences that are active now
+
+	Result []*AlertSilence
+}
+
+// GetActiveAlertSilencesQuery returns the silences that are active at Now
+// and match the alert.
+type GetActiveAlertSilencesQuery struct {
+	OrgId       int64
+	AlertId     int64
+	DashboardId int64
+	RuleName    string
+	Now         time.Time
+
+	Result []*AlertSilence
+}
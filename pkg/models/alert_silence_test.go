@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilence(t *testing.T) {
+	Convey("Testing alert silences", t, func() {
+		start := time.Date(2017, 5, 1, 22, 0, 0, 0, time.UTC)
+
+		Convey("Silence is active between start and end", func() {
+			silence := &AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(2 * time.Hour)}
+			So(silence.IsActive(start.Add(-time.Minute)), ShouldBeFalse)
+			So(silence.IsActive(start), ShouldBeTrue)
+			So(silence.IsActive(start.Add(119*time.Minute)), ShouldBeTrue)
+			So(silence.IsActive(start.Add(2*time.Hour)), ShouldBeFalse)
+		})
+
+		Convey("Recurring window repeats until it expires", func() {
+			silence := &AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(2 * time.Hour), Recurrence: ALERT_SILENCE_RECURRENCE_DAILY}
+			So(silence.IsActive(start.Add(24*time.Hour+time.Hour)), ShouldBeTrue)
+			So(silence.IsActive(start.Add(24*time.Hour+3*time.Hour)), ShouldBeFalse)
+
+			silence.ExpiresAt = start.Add(30 * time.Hour)
+			So(silence.IsActive(start.Add(48*time.Hour+time.Hour)), ShouldBeFalse)
+		})
+
+		Convey("Expired silence is not active", func() {
+			silence := &AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(2 * time.Hour), ExpiresAt: start.Add(time.Hour)}
+			So(silence.IsActive(start.Add(30*time.Minute)), ShouldBeTrue)
+			So(silence.IsActive(start.Add(90*time.Minute)), ShouldBeFalse)
+		})
+
+		Convey("All matchers that are set have to match", func() {
+			silence := &AlertSilence{DashboardId: 2, RulePattern: "^cpu", DashboardTag: "prod"}
+			So(silence.Matches(1, 2, "cpu high", []string{"prod", "db"}), ShouldBeTrue)
+			So(silence.Matches(1, 3, "cpu high", []string{"prod"}), ShouldBeFalse)
+			So(silence.Matches(1, 2, "memory high", []string{"prod"}), ShouldBeFalse)
+			So(silence.Matches(1, 2, "cpu high", []string{"staging"}), ShouldBeFalse)
+		})
+
+		Convey("Validation", func() {
+			So((&AlertSilence{StartsAt: start, EndsAt: start.Add(time.Hour)}).Validate(), ShouldEqual, ErrAlertSilenceMissingMatcher)
+			So((&AlertSilence{RulePattern: "(", StartsAt: start, EndsAt: start.Add(time.Hour)}).Validate(), ShouldEqual, ErrAlertSilenceInvalidPattern)
+			So((&AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start}).Validate(), ShouldEqual, ErrAlertSilenceInvalidWindow)
+			So((&AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(25 * time.Hour), Recurrence: ALERT_SILENCE_RECURRENCE_DAILY}).Validate(), ShouldEqual, ErrAlertSilenceInvalidWindow)
+			So((&AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(time.Hour), Recurrence: "monthly"}).Validate(), ShouldEqual, ErrAlertSilenceInvalidWindow)
+			So((&AlertSilence{AlertId: 1, StartsAt: start, EndsAt: start.Add(25 * time.Hour), Recurrence: ALERT_SILENCE_RECURRENCE_WEEKLY}).Validate(), ShouldBeNil)
+		})
+	})
+}
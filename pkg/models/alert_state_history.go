@@ -0,0 +1,56 @@
+package models
+
+import (
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+const DEFAULT_ALERT_STATE_HISTORY_LIMIT = 100
+
+// AlertStateHistory is one state change of an alert. Data holds the eval
+// matches, error or no data flag of the evaluation that changed the state.
+type AlertStateHistory struct {
+	Id          int64
+	OrgId       int64
+	AlertId     int64
+	DashboardId int64
+	PanelId     int64
+	AlertName   string
+	NodeId      string
+	PrevState   AlertStateType
+	NewState    AlertStateType
+	Data        *simplejson.Json
+	Epoch       int64
+}
+
+type SaveAlertStateHistoryCommand struct {
+	OrgId       int64
+	AlertId     int64
+	DashboardId int64
+	PanelId     int64
+	AlertName   string
+	NodeId      string
+	PrevState   AlertStateType
+	NewState    AlertStateType
+	Data        *simplejson.Json
+	Epoch       int64
+
+	Result *AlertStateHistory
+}
+
+// GetAlertStateHistoryQuery returns the state changes newest first. Before
+// is the cursor of the page, only changes with a lower id are returned.
+// NextCursor of the result is 0 on the last page.
+type GetAlertStateHistoryQuery struct {
+	OrgId       int64
+	AlertId     int64
+	DashboardId int64
+	NodeId      string
+	State       []string
+	From        int64
+	To          int64
+	Before      int64
+	Limit       int
+
+	Result     []*AlertStateHistory
+	NextCursor int64
+}
@@ -0,0 +1,27 @@
+package models
+
+const (
+	CLUSTER_NOTIFICATION_TYPE_DASHBOARD  = "dashboard"
+	CLUSTER_NOTIFICATION_TYPE_ALERTS     = "alerts"
+	CLUSTER_NOTIFICATION_TYPE_DATASOURCE = "datasource"
+)
+
+// ClusterNotification tells the nodes of a cluster that an item they may have
+// cached was changed by NodeId. ItemId is the dashboard or datasource id, 0
+// means every item of the type in the org.
+type ClusterNotification struct {
+	Id      int64  `json:"id"`
+	NodeId  string `json:"nodeId"`
+	Type    string `json:"type"`
+	OrgId   int64  `json:"orgId"`
+	ItemId  int64  `json:"itemId"`
+	Created int64  `json:"created"`
+}
+
+// GetClusterNotificationsQuery returns the notifications created at or after
+// Since in the order they were added.
+type GetClusterNotificationsQuery struct {
+	Since int64
+
+	Result []*ClusterNotification
+}
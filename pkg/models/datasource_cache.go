@@ -93,3 +93,15 @@ func (ds *DataSource) GetHttpTransport() (*http.Transport, error) {
 
 	return transport, nil
 }
+
+// EvictDataSourceTransport drops the cached transport of the datasource, so
+// the next request builds it from the current datasource settings.
+func EvictDataSourceTransport(id int64) {
+	ptc.Lock()
+	defer ptc.Unlock()
+
+	if t, ok := ptc.cache[id]; ok {
+		t.Transport.CloseIdleConnections()
+		delete(ptc.cache, id)
+	}
+}
@@ -29,6 +29,18 @@ func TestDataSourceCache(t *testing.T) {
 		Convey("Should be using the cached proxy", func() {
 			So(t2, ShouldEqual, t1)
 		})
+
+		Convey("Should build a new proxy after eviction", func() {
+			EvictDataSourceTransport(2)
+			t3, err := ds.GetHttpTransport()
+			So(err, ShouldBeNil)
+			So(t3, ShouldEqual, t1)
+
+			EvictDataSourceTransport(1)
+			t4, err := ds.GetHttpTransport()
+			So(err, ShouldBeNil)
+			So(t4, ShouldNotEqual, t1)
+		})
 	})
 
 	Convey("When getting kubernetes datasource proxy", t, func() {
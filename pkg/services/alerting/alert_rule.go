@@ -0,0 +1,121 @@
+package alerting
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// SaveAlertRuleCommand saves an alert rule that is not part of a dashboard.
+// Rule has the format of the alert of a panel, except that the query of each
+// condition holds the query model and the datasource itself:
+//
+//	"query": {"params": ["A", "5m", "now"], "datasource": "graphite", "model": {"target": "..."}}
+//
+// The default datasource is used if the query has neither datasource nor
+// datasourceId. Id is 0 to create a rule.
+type SaveAlertRuleCommand struct {
+	Id     int64
+	OrgId  int64
+	UserId int64
+	Rule   *simplejson.Json
+
+	Result *m.Alert
+}
+
+func init() {
+	bus.AddHandler("alerting", saveAlertRule)
+}
+
+func saveAlertRule(cmd *SaveAlertRuleCommand) error {
+	alert, err := newAlertFromRule(cmd.OrgId, cmd.Rule)
+	if err != nil {
+		return err
+	}
+	alert.Id = cmd.Id
+
+	saveCmd := &m.SaveAlertRuleCommand{
+		OrgId:  cmd.OrgId,
+		UserId: cmd.UserId,
+		Alert:  alert,
+	}
+	if err := bus.Dispatch(saveCmd); err != nil {
+		return err
+	}
+
+	cmd.Result = saveCmd.Result
+	return nil
+}
+
+func newAlertFromRule(orgId int64, ruleJson *simplejson.Json) (*m.Alert, error) {
+	if ruleJson == nil {
+		return nil, ValidationError{Reason: "Missing alert rule"}
+	}
+
+	jsonAlert, err := copyJson(ruleJson)
+	if err != nil {
+		return nil, err
+	}
+
+	frequency, err := getTimeDurationStringToSeconds(jsonAlert.Get("frequency").MustString("60s"))
+	if err != nil {
+		return nil, ValidationError{Reason: "Could not parse frequency"}
+	}
+	jsonAlert.Del("id")
+
+	alert := &m.Alert{
+		OrgId:     orgId,
+		Name:      jsonAlert.Get("name").MustString(),
+		Handler:   jsonAlert.Get("handler").MustInt64(1),
+		Message:   jsonAlert.Get("message").MustString(),
+		Severity:  jsonAlert.Get("severity").MustString(),
+		Frequency: frequency,
+		Settings:  jsonAlert,
+	}
+
+	if alert.Name == "" {
+		return nil, ValidationError{Reason: "Alert rule is missing a name"}
+	}
+
+	for _, condition := range jsonAlert.Get("conditions").MustArray() {
+		jsonQuery := simplejson.NewFromAny(condition).Get("query")
+
+		params := jsonQuery.Get("params").MustArray()
+		if len(params) != 3 {
+			return nil, ValidationError{Reason: "Query params of a condition must be the refId, from and to"}
+		}
+
+		model, hasModel := jsonQuery.CheckGet("model")
+		if !hasModel {
+			return nil, ValidationError{Reason: fmt.Sprintf("Query(%v) is missing the query model", params[0])}
+		}
+		if _, hasRefId := model.CheckGet("refId"); !hasRefId {
+			model.Set("refId", params[0])
+		}
+
+		if datasourceId := jsonQuery.Get("datasourceId").MustInt64(); datasourceId != 0 {
+			query := &m.GetDataSourceByIdQuery{Id: datasourceId, OrgId: orgId}
+			if err := bus.Dispatch(query); err != nil {
+				return nil, ValidationError{Err: err, Reason: fmt.Sprintf("Query(%v) refers to a datasource that cannot be found", params[0])}
+			}
+		} else {
+			datasource, err := lookupDatasource(orgId, jsonQuery.Get("datasource").MustString())
+			if err != nil {
+				return nil, ValidationError{Err: err, Reason: fmt.Sprintf("Query(%v) refers to a datasource that cannot be found", params[0])}
+			}
+			jsonQuery.Set("datasourceId", datasource.Id)
+		}
+	}
+
+	// validate
+	if _, err := NewRuleFromDBAlert(alert); err != nil {
+		if _, ok := err.(ValidationError); ok {
+			return nil, err
+		}
+		return nil, ValidationError{Err: err}
+	}
+
+	return alert, nil
+}
@@ -0,0 +1,89 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStandaloneAlertRule(t *testing.T) {
+	Convey("Parsing standalone alert rules", t, func() {
+		RegisterCondition("query", func(model *simplejson.Json, index int) (Condition, error) {
+			return &FakeCondition{}, nil
+		})
+
+		defaultDs := &m.DataSource{Id: 12, OrgId: 1, Name: "I am default", IsDefault: true}
+		graphiteDs := &m.DataSource{Id: 15, OrgId: 1, Name: "graphite"}
+
+		bus.AddHandler("test", func(query *m.GetDataSourcesQuery) error {
+			query.Result = []*m.DataSource{defaultDs, graphiteDs}
+			return nil
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourceByNameQuery) error {
+			if query.Name == graphiteDs.Name {
+				query.Result = graphiteDs
+				return nil
+			}
+			return m.ErrDataSourceNotFound
+		})
+
+		bus.AddHandler("test", func(query *m.GetDataSourceByIdQuery) error {
+			if query.Id == graphiteDs.Id && query.OrgId == graphiteDs.OrgId {
+				query.Result = graphiteDs
+				return nil
+			}
+			return m.ErrDataSourceNotFound
+		})
+
+		ruleJson := func(query string) *simplejson.Json {
+			json, err := simplejson.NewJson([]byte(`{
+				"name": "cpu usage",
+				"message": "cpu is high",
+				"frequency": "30s",
+				"conditions": [{
+					"type": "query",
+					"query": ` + query + `,
+					"reducer": {"type": "avg", "params": []},
+					"evaluator": {"type": "gt", "params": [80]}
+				}]
+			}`))
+			So(err, ShouldBeNil)
+			return json
+		}
+
+		Convey("Query references the datasource by name", func() {
+			alert, err := newAlertFromRule(1, ruleJson(`{"params": ["A", "5m", "now"], "datasource": "graphite", "model": {"target": "cpu"}}`))
+			So(err, ShouldBeNil)
+			So(alert.IsStandalone(), ShouldBeTrue)
+			So(alert.ValidToSave(), ShouldBeTrue)
+			So(alert.Name, ShouldEqual, "cpu usage")
+			So(alert.Frequency, ShouldEqual, 30)
+
+			query := alert.Settings.Get("conditions").GetIndex(0).Get("query")
+			So(query.Get("datasourceId").MustInt64(), ShouldEqual, 15)
+			So(query.Get("model").Get("refId").MustString(), ShouldEqual, "A")
+		})
+
+		Convey("Query without datasource uses the default", func() {
+			alert, err := newAlertFromRule(1, ruleJson(`{"params": ["A", "5m", "now"], "model": {"target": "cpu"}}`))
+			So(err, ShouldBeNil)
+			So(alert.Settings.Get("conditions").GetIndex(0).Get("query").Get("datasourceId").MustInt64(), ShouldEqual, 12)
+		})
+
+		Convey("Datasource of another org is refused", func() {
+			_, err := newAlertFromRule(2, ruleJson(`{"params": ["A", "5m", "now"], "datasourceId": 15, "model": {"target": "cpu"}}`))
+			_, isValidationErr := err.(ValidationError)
+			So(isValidationErr, ShouldBeTrue)
+		})
+
+		Convey("Query without model is refused", func() {
+			_, err := newAlertFromRule(1, ruleJson(`{"params": ["A", "5m", "now"], "datasource": "graphite"}`))
+			_, isValidationErr := err.(ValidationError)
+			So(isValidationErr, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,215 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+const maxBacktestSteps = 100000
+
+// Backtest is set on the eval context of each step of a backtest. Conditions
+// fetch their data once for the whole backtest, keep it in Data by condition
+// index, and evaluate each step on the part of it their query range covers.
+type Backtest struct {
+	From time.Time // first step
+	To   time.Time // last step
+	At   time.Time // current step
+
+	Data map[int]interface{}
+}
+
+// BacktestCommand replays a rule over a past window at its frequency, without
+// saving state or sending notifications. The rule is the stored alert AlertId,
+// or the alert of panel PanelId of Dashboard.
+type BacktestCommand struct {
+	OrgId     int64
+	AlertId   int64
+	Dashboard *simplejson.Json
+	PanelId   int64
+	From      time.Time
+	To        time.Time
+
+	Result *BacktestResult
+}
+
+type BacktestResult struct {
+	States        []*BacktestState        `json:"states"`
+	Transitions   []*BacktestTransition   `json:"transitions"`
+	Notifications []*BacktestNotification `json:"notifications"`
+}
+
+type BacktestState struct {
+	Time        int64            `json:"time"`
+	State       m.AlertStateType `json:"state"`
+	Firing      bool             `json:"firing"`
+	NoData      bool             `json:"noData"`
+	Error       string           `json:"error,omitempty"`
+	EvalMatches []*EvalMatch     `json:"evalMatches"`
+}
+
+type BacktestTransition struct {
+	Time      int64            `json:"time"`
+	PrevState m.AlertStateType `json:"prevState"`
+	NewState  m.AlertStateType `json:"newState"`
+}
+
+type BacktestNotification struct {
+	Time              int64            `json:"time"`
+	NotifierId        int64            `json:"notifierId"`
+	NotifierName      string           `json:"notifierName"`
+	State             m.AlertStateType `json:"state"`
+	Reminder          bool             `json:"reminder"`
+	StartedInstances  []string         `json:"startedInstances"`
+	ResolvedInstances []string         `json:"resolvedInstances"`
+}
+
+func init() {
+	bus.AddHandler("alerting", handleBacktestCommand)
+}
+
+func handleBacktestCommand(cmd *BacktestCommand) error {
+	rule, err := getBacktestRule(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !cmd.From.Before(cmd.To) {
+		return ValidationError{Reason: "Backtest must end after it starts"}
+	}
+	if cmd.To.After(time.Now()) {
+		return ValidationError{Reason: "Backtest cannot end in the future"}
+	}
+
+	step := time.Duration(rule.Frequency) * time.Second
+	if step <= 0 {
+		return ValidationError{Reason: "Alert rule has no frequency"}
+	}
+	if steps := cmd.To.Sub(cmd.From) / step; steps > maxBacktestSteps {
+		return ValidationError{Reason: fmt.Sprintf("Backtest would evaluate the rule %d times, at most %d are allowed", steps, maxBacktestSteps)}
+	}
+
+	notificationIds, err := getNotificationIds(rule)
+	if err != nil {
+		return err
+	}
+
+	notificationsQuery := &m.GetAlertNotificationsToSendQuery{OrgId: rule.OrgId, Ids: notificationIds}
+	if err := bus.Dispatch(notificationsQuery); err != nil {
+		return err
+	}
+
+	cmd.Result = backtestRule(rule, notificationsQuery.Result, cmd.From, cmd.To)
+	return nil
+}
+
+func getBacktestRule(cmd *BacktestCommand) (*Rule, error) {
+	if cmd.AlertId != 0 {
+		query := &m.GetAlertByIdQuery{Id: cmd.AlertId}
+		if err := bus.Dispatch(query); err != nil || query.Result.OrgId != cmd.OrgId {
+			return nil, ValidationError{Reason: fmt.Sprintf("Could not find alert with id %d", cmd.AlertId)}
+		}
+		return NewRuleFromDBAlert(query.Result)
+	}
+
+	return getRuleFromDashboard(cmd.Dashboard, cmd.OrgId, cmd.PanelId)
+}
+
+// backtestRule evaluates the rule at each step from from to to, and replays
+// the state, the series and the notifications in memory. The rule starts in
+// the ok state.
+func backtestRule(rule *Rule, notifications []*m.AlertNotification, from, to time.Time) *BacktestResult {
+	result := &BacktestResult{
+		States:        make([]*BacktestState, 0),
+		Transitions:   make([]*BacktestTransition, 0),
+		Notifications: make([]*BacktestNotification, 0),
+	}
+
+	handler := NewEvalHandler()
+	backtest := &Backtest{From: from, To: to, Data: make(map[int]interface{})}
+	instances := make(map[string]*m.AlertInstance)
+	lastSent := make(map[int64]time.Time)
+
+	state, since := m.AlertStateOK, from
+	step := time.Duration(rule.Frequency) * time.Second
+	for at := from; !at.After(to); at = at.Add(step) {
+		rule.State = state
+		rule.NewStateDate = since
+		backtest.At = at
+
+		evalContext := NewEvalContext(context.Background(), rule)
+		evalContext.Backtest = backtest
+		handler.Eval(evalContext)
+
+		stepState := &BacktestState{
+			Time:        at.Unix() * 1000,
+			State:       rule.State,
+			Firing:      evalContext.Firing,
+			NoData:      evalContext.NoDataFound,
+			EvalMatches: evalContext.EvalMatches,
+		}
+		if evalContext.Error != nil {
+			stepState.Error = evalContext.Error.Error()
+		}
+		result.States = append(result.States, stepState)
+
+		existing := make([]*m.AlertInstance, 0, len(instances))
+		for _, instance := range instances {
+			existing = append(existing, instance)
+		}
+		for _, instance := range diffInstances(evalContext, existing) {
+			instances[instance.InstanceKey] = instance
+		}
+
+		stateChanged := evalContext.ShouldUpdateAlertState()
+		if stateChanged {
+			result.Transitions = append(result.Transitions, &BacktestTransition{
+				Time:      stepState.Time,
+				PrevState: evalContext.PrevAlertState,
+				NewState:  rule.State,
+			})
+			state, since = rule.State, at
+		}
+
+		// mirrors the result handler and NotifierBase.ShouldNotify
+		if (stateChanged && evalContext.ShouldSendNotification()) || (!stateChanged && rule.State == m.AlertStateAlerting) {
+			instancesChanged := len(evalContext.StartedInstances) > 0 || len(evalContext.ResolvedInstances) > 0
+			for _, notification := range notifications {
+				reminder := !stateChanged && !instancesChanged
+				if reminder {
+					if !notification.SendReminder || notification.Frequency <= 0 {
+						continue
+					}
+					if last, sent := lastSent[notification.Id]; sent && at.Sub(last) < notification.Frequency {
+						continue
+					}
+				}
+
+				lastSent[notification.Id] = at
+				result.Notifications = append(result.Notifications, &BacktestNotification{
+					Time:              stepState.Time,
+					NotifierId:        notification.Id,
+					NotifierName:      notification.Name,
+					State:             rule.State,
+					Reminder:          reminder,
+					StartedInstances:  backtestInstanceNames(evalContext.StartedInstances),
+					ResolvedInstances: backtestInstanceNames(evalContext.ResolvedInstances),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+func backtestInstanceNames(matches []*EvalMatch) []string {
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, InstanceName(match.Metric, match.Tags))
+	}
+	return names
+}
@@ -0,0 +1,90 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// backtestCondition fires during the given minutes after the start of the
+// backtest and has no data during others.
+type backtestCondition struct {
+	start  time.Time
+	firing map[int]bool
+	noData map[int]bool
+}
+
+func (c *backtestCondition) Eval(context *EvalContext) (*ConditionResult, error) {
+	minute := int(context.Backtest.At.Sub(c.start) / time.Minute)
+	result := &ConditionResult{Firing: c.firing[minute], NoDataFound: c.noData[minute]}
+	if result.Firing {
+		result.EvalMatches = []*EvalMatch{{Metric: "cpu"}}
+	}
+	return result, nil
+}
+
+func TestBacktest(t *testing.T) {
+	Convey("Backtesting a rule", t, func() {
+		start := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+		condition := &backtestCondition{start: start, firing: map[int]bool{2: true, 3: true, 4: true, 5: true}, noData: map[int]bool{}}
+		rule := &Rule{
+			Id:                  1,
+			OrgId:               1,
+			Frequency:           60,
+			Conditions:          []Condition{condition},
+			NoDataState:         m.NoDataSetNoData,
+			ExecutionErrorState: m.ExecutionErrorSetAlerting,
+		}
+		notifications := []*m.AlertNotification{
+			{Id: 1, Name: "ops"},
+			{Id: 2, Name: "pager", SendReminder: true, Frequency: 2 * time.Minute},
+		}
+
+		Convey("Returns the state at each step and its transitions", func() {
+			result := backtestRule(rule, notifications, start, start.Add(7*time.Minute))
+
+			So(len(result.States), ShouldEqual, 8)
+			So(result.States[1].State, ShouldEqual, m.AlertStateOK)
+			So(result.States[2].State, ShouldEqual, m.AlertStateAlerting)
+			So(result.States[6].State, ShouldEqual, m.AlertStateOK)
+
+			So(len(result.Transitions), ShouldEqual, 2)
+			So(result.Transitions[0].Time, ShouldEqual, start.Add(2*time.Minute).Unix()*1000)
+			So(result.Transitions[0].NewState, ShouldEqual, m.AlertStateAlerting)
+			So(result.Transitions[1].NewState, ShouldEqual, m.AlertStateOK)
+		})
+
+		Convey("Returns the notifications and reminders it would send", func() {
+			result := backtestRule(rule, notifications, start, start.Add(7*time.Minute))
+
+			// both notify at minute 2 and 6, the pager reminds at minute 4
+			So(len(result.Notifications), ShouldEqual, 5)
+			So(result.Notifications[0].StartedInstances, ShouldResemble, []string{"cpu"})
+			So(result.Notifications[2].NotifierName, ShouldEqual, "pager")
+			So(result.Notifications[2].Reminder, ShouldBeTrue)
+			So(result.Notifications[2].Time, ShouldEqual, start.Add(4*time.Minute).Unix()*1000)
+			So(result.Notifications[4].ResolvedInstances, ShouldResemble, []string{"cpu"})
+		})
+
+		Convey("Waits for the for duration of the rule", func() {
+			rule.For = 2 * time.Minute
+			result := backtestRule(rule, notifications, start, start.Add(7*time.Minute))
+
+			So(result.States[2].State, ShouldEqual, m.AlertStatePending)
+			So(result.States[3].State, ShouldEqual, m.AlertStatePending)
+			So(result.States[4].State, ShouldEqual, m.AlertStateAlerting)
+			So(result.Notifications[0].Time, ShouldEqual, start.Add(4*time.Minute).Unix()*1000)
+		})
+
+		Convey("Applies the no data state on every step", func() {
+			condition.noData[1] = true
+			result := backtestRule(rule, notifications, start, start.Add(2*time.Minute))
+
+			So(result.States[1].State, ShouldEqual, m.AlertStateNoData)
+			So(result.States[1].NoData, ShouldBeTrue)
+			So(len(result.Transitions), ShouldEqual, 2)
+		})
+	})
+}
@@ -3,10 +3,13 @@ package alerting
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/hashring"
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	m "github.com/grafana/grafana/pkg/models"
 	s "github.com/grafana/grafana/pkg/setting"
 )
@@ -23,17 +26,35 @@ type ValidateDashboardAlertsCommand struct {
 	Dashboard *m.Dashboard
 }
 
+// PendingAlertJobCountQuery returns the number of queued alert jobs and, in
+// clustered mode, the end of the interval this node is running rules for.
 type PendingAlertJobCountQuery struct {
-	ResultCount int
+	ResultCount          int
+	ResultScheduledUntil int64
 }
 
+// ScheduleAlertsForPartitionCommand makes this node run the rules that the
+// consistent hash ring built from Nodes assigns to NodeId. Interval is the
+// last heartbeat; the rules run at their frequency during the minute after it.
+// It is the fencing token too, the command is refused once it is stale.
 type ScheduleAlertsForPartitionCommand struct {
-	PartId    int
-	NodeCount int
-	Interval  int64
+	NodeId   string
+	PartId   int32
+	Nodes    []string
+	Interval int64
+}
+
+// RefreshAlertRulesCommand reloads the rules this node runs in clustered mode
+// after a peer changed them. It returns the number of rules still run.
+type RefreshAlertRulesCommand struct {
+	ResultCount int
 }
 
+// ScheduleMissingAlertsCommand runs the missing alerts on NodeId. FencingToken
+// is the heartbeat the node checked in for them at.
 type ScheduleMissingAlertsCommand struct {
+	NodeId        string
+	FencingToken  int64
 	MissingAlerts []*m.Alert
 	Result        []*Rule
 }
@@ -46,6 +67,7 @@ func init() {
 	bus.AddHandler("alerting", getPendingAlertJobCount)
 	bus.AddHandler("alerting", scheduleAlertsForPartition)
 	bus.AddHandler("alerting", scheduleMissingAlerts)
+	bus.AddHandler("alerting", refreshAlertRules)
 }
 
 func validateDashboardAlerts(cmd *ValidateDashboardAlertsCommand) error {
@@ -85,6 +107,7 @@ func getPendingAlertJobCount(query *PendingAlertJobCountQuery) error {
 		return errors.New("Alerting engine is not initialized")
 	}
 	query.ResultCount = len(engine.execQueue)
+	query.ResultScheduledUntil = engine.partition.ScheduledUntil()
 	return nil
 }
 
@@ -92,37 +115,60 @@ func scheduleAlertsForPartition(cmd *ScheduleAlertsForPartitionCommand) error {
 	if engine == nil {
 		return errors.New("Alerting engine is not initialized")
 	}
-	if cmd.NodeCount == 0 {
+	if len(cmd.Nodes) == 0 {
 		return errors.New("Node count is 0")
 	}
-	if cmd.PartId >= cmd.NodeCount {
-		return errors.New(fmt.Sprintf("Invalid partition id %v (node count = %v)", cmd.PartId, cmd.NodeCount))
+	ring := hashring.New(s.ClusteringVirtualNodes, cmd.Nodes...)
+	if ring.Len() != len(cmd.Nodes) || !containsNode(cmd.Nodes, cmd.NodeId) {
+		return errors.New(fmt.Sprintf("Invalid node %v (nodes = %v)", cmd.NodeId, cmd.Nodes))
+	}
+	if err := validateFencingToken(cmd.Interval); err != nil {
+		return err
 	}
 	rules := engine.ruleReader.Fetch()
-	filterCount := 0
-	intervalEnd := time.Unix(cmd.Interval, 0).Add(time.Minute)
-	for _, rule := range rules {
-		evalDateTrunc := rule.EvalDate.Truncate(time.Minute)
-		// handle frequency greater than 1 min
-		nextEvalDate := evalDateTrunc.Add(time.Duration(rule.Frequency) * time.Second)
-		if nextEvalDate.Before(intervalEnd) || nextEvalDate.Equal(intervalEnd) {
-			if rule.Id%int64(cmd.NodeCount) == int64(cmd.PartId) {
-				engine.execQueue <- &Job{Rule: rule}
-				filterCount++
-				engine.log.Debug(fmt.Sprintf("Scheduled Rule : %v for interval=%v", rule, cmd.Interval))
-			} else {
-				engine.log.Debug(fmt.Sprintf("Skipped Rule : %v for interval=%v, partition id=%v, nodeCount=%v", rule, cmd.Interval, cmd.PartId, cmd.NodeCount))
-			}
-		} else {
-			engine.log.Debug(fmt.Sprintf("Skipped Rule : %v for interval=%v, intervalEnd=%v, nextEvalDate=%v", rule, cmd.Interval, intervalEnd, nextEvalDate))
+	start := cmd.Interval + 60
+	ownedCount := engine.partition.Update(rules, func(rule *Rule) bool {
+		return ring.Get(strconv.FormatInt(rule.Id, 10)) == cmd.NodeId
+	}, cmd.NodeId, strconv.FormatInt(int64(cmd.PartId), 10), start, start+60)
+	engine.log.Info(fmt.Sprintf("%v/%v rules scheduled for execution for node %v (node count = %v, interval = %v)",
+		ownedCount, len(rules), cmd.NodeId, len(cmd.Nodes), start))
+	return nil
+}
+
+func refreshAlertRules(cmd *RefreshAlertRulesCommand) error {
+	if engine == nil {
+		return errors.New("Alerting engine is not initialized")
+	}
+	cmd.ResultCount = engine.partition.Refresh(engine.ruleReader.Fetch())
+	engine.log.Debug("Refreshed alert rules", "count", cmd.ResultCount)
+	return nil
+}
+
+// validateFencingToken refuses work scheduled for a heartbeat interval that
+// has passed, e.g. by a node that was paused after it checked in.
+func validateFencingToken(token int64) error {
+	if err := bus.Dispatch(&m.ValidateFencingTokenQuery{Token: token}); err != nil {
+		if err == m.ErrStaleFencingToken {
+			schedulerCommandsLog.Warn("Refusing alerts scheduled for stale heartbeat", "fencingToken", token)
 		}
+		return err
 	}
-	engine.log.Info(fmt.Sprintf("%v/%v rules scheduled for execution for partition %v/%v",
-		filterCount, len(rules), cmd.PartId, cmd.NodeCount))
 	return nil
 }
 
+func containsNode(nodes []string, nodeId string) bool {
+	for _, node := range nodes {
+		if node == nodeId {
+			return true
+		}
+	}
+	return false
+}
+
 func scheduleMissingAlerts(cmd *ScheduleMissingAlertsCommand) error {
+	if err := validateFencingToken(cmd.FencingToken); err != nil {
+		return err
+	}
 	//transform each alert to rule
 	res := make([]*Rule, 0)
 	missingAlerts := cmd.MissingAlerts
@@ -140,13 +186,13 @@ func scheduleMissingAlerts(cmd *ScheduleMissingAlertsCommand) error {
 		if frequency >= 60 && frequency <= s.DefaultMissingAlertsDelay {
 			factor := 1
 			for factor <= noOfIterations {
-				res = submitAlertToEngine(ruleDef, res, factor)
+				res = submitAlertToEngine(ruleDef, res, factor, cmd.NodeId)
 				factor = factor + 1
 			}
 		} else if frequency > s.DefaultMissingAlertsDelay { //For frequency greater than 10 minutes just go back to previous missed frequency
 			frequencyInMin := int(frequency / 60)
 			factor := 1 + frequencyInMin
-			res = submitAlertToEngine(ruleDef, res, factor)
+			res = submitAlertToEngine(ruleDef, res, factor, cmd.NodeId)
 		}
 	}
 	cmd.Result = res
@@ -154,12 +200,17 @@ func scheduleMissingAlerts(cmd *ScheduleMissingAlertsCommand) error {
 	return nil
 }
 
-var submitAlertToEngine = func(ruleDef *m.Alert, res []*Rule, factor int) []*Rule {
+var submitAlertToEngine = func(ruleDef *m.Alert, res []*Rule, factor int, nodeId string) []*Rule {
 	if model, err := ModifiedRuleFromDBAlert(ruleDef, factor); err != nil {
 		schedulerCommandsLog.Error("Could not build alert model for rule", "ruleId", ruleDef.Id, "error", err)
 	} else {
 		res = append(res, model)
-		engine.execQueue <- &Job{Rule: model}
+		//the rule is evaluated for the minute factor minutes ago and claimed for the
+		//time the scheduler had it due then, so a missed run is only evaluated once
+		job := &Job{Rule: model, NodeId: nodeId, Partition: m.CLN_ALERT_RUN_TYPE_MISSING}
+		job.ScheduledAt = lastDueBefore(job, time.Now().Truncate(time.Minute).Unix()-int64(factor*60)+1)
+		metrics.M_Clustering_Scheduled_Jobs.With(m.CLN_ALERT_RUN_TYPE_MISSING).Inc(1)
+		engine.execQueue <- job
 		schedulerCommandsLog.Debug(fmt.Sprintf("Scheduled missed Rule : %v", model.Name))
 	}
 	return res
@@ -2,18 +2,38 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
 	m "github.com/grafana/grafana/pkg/models"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 func TestScheduleMissingAlerts(t *testing.T) {
-	submitAlertToEngine = func(ruleDef *m.Alert, res []*Rule, factor int) []*Rule {
+	submitAlertToEngine = func(ruleDef *m.Alert, res []*Rule, factor int, nodeId string) []*Rule {
 		res = append(res, &Rule{})
 		return res
 	}
 
 	Convey("Test scheduling of missing alerts", t, func() {
+		bus.AddHandler("test", func(query *m.ValidateFencingTokenQuery) error {
+			if query.Token < 1493233440 {
+				return m.ErrStaleFencingToken
+			}
+			return nil
+		})
+
+		Convey("Test missing alerts from a stale heartbeat are refused", func() {
+			cmd := &ScheduleMissingAlertsCommand{
+				FencingToken:  1493233380,
+				MissingAlerts: []*m.Alert{{Frequency: 60}},
+			}
+
+			err := scheduleMissingAlerts(cmd)
+			So(err, ShouldEqual, m.ErrStaleFencingToken)
+			So(len(cmd.Result), ShouldEqual, 0)
+		})
 
 		Convey("Test No of iterations for alert with frequency of 60s", func() {
 			alert := make([]*m.Alert, 0)
@@ -21,6 +41,7 @@ func TestScheduleMissingAlerts(t *testing.T) {
 			alert = append(alert, alert1)
 
 			cmd := &ScheduleMissingAlertsCommand{
+				FencingToken:  1493233440,
 				MissingAlerts: alert,
 			}
 
@@ -35,6 +56,7 @@ func TestScheduleMissingAlerts(t *testing.T) {
 			alert = append(alert, alert1)
 
 			cmd := &ScheduleMissingAlertsCommand{
+				FencingToken:  1493233440,
 				MissingAlerts: alert,
 			}
 
@@ -49,6 +71,7 @@ func TestScheduleMissingAlerts(t *testing.T) {
 			alert = append(alert, alert1)
 
 			cmd := &ScheduleMissingAlertsCommand{
+				FencingToken:  1493233440,
 				MissingAlerts: alert,
 			}
 
@@ -58,3 +81,133 @@ func TestScheduleMissingAlerts(t *testing.T) {
 		})
 	})
 }
+
+type fakeRuleReader struct {
+	rules []*Rule
+}
+
+func (f *fakeRuleReader) Fetch() []*Rule {
+	return f.rules
+}
+
+func TestScheduleAlertsForPartition(t *testing.T) {
+	Convey("Test scheduling of alerts for a node on the hash ring", t, func() {
+		rules := make([]*Rule, 0)
+		for i := 1; i <= 100; i++ {
+			rules = append(rules, &Rule{Id: int64(i), Frequency: 60})
+		}
+		prevEngine := engine
+		engine = &Engine{
+			execQueue:  make(chan *Job, 1000),
+			partition:  newPartitionScheduler(),
+			ruleReader: &fakeRuleReader{rules: rules},
+			log:        log.New("alerting.engine"),
+		}
+		defer func() { engine = prevEngine }()
+		bus.AddHandler("test", func(query *m.ValidateFencingTokenQuery) error {
+			if query.Token < 1493233500 {
+				return m.ErrStaleFencingToken
+			}
+			return nil
+		})
+
+		nodes := []string{"node1:3000", "node2:3000", "node3:3000"}
+		interval := int64(1493233500)
+		runInterval := func(nodeId string, interval int64) []*Job {
+			jobs := make([]*Job, 0)
+			err := scheduleAlertsForPartition(&ScheduleAlertsForPartitionCommand{
+				NodeId:   nodeId,
+				Nodes:    nodes,
+				Interval: interval,
+			})
+			So(err, ShouldBeNil)
+			for t := interval + 60; t < interval+120; t++ {
+				engine.partition.Tick(time.Unix(t, 0), engine.execQueue)
+				for len(engine.execQueue) > 0 {
+					jobs = append(jobs, <-engine.execQueue)
+				}
+			}
+			return jobs
+		}
+
+		Convey("Every rule is scheduled on exactly one node", func() {
+			scheduled := make(map[int64]string)
+			for _, node := range nodes {
+				engine.partition = newPartitionScheduler()
+				for _, job := range runInterval(node, interval) {
+					_, exists := scheduled[job.Rule.Id]
+					So(exists, ShouldBeFalse)
+					scheduled[job.Rule.Id] = node
+				}
+			}
+			So(len(scheduled), ShouldEqual, len(rules))
+		})
+
+		Convey("Rules run at their own frequency", func() {
+			engine.ruleReader = &fakeRuleReader{rules: []*Rule{
+				{Id: 1, Frequency: 10},
+				{Id: 2, Frequency: 300},
+			}}
+			counts := make(map[int64]int)
+			for i := int64(0); i < 5; i++ {
+				for _, node := range nodes {
+					engine.partition = newPartitionScheduler()
+					for _, job := range runInterval(node, interval+i*60) {
+						counts[job.Rule.Id]++
+					}
+				}
+			}
+			So(counts[1], ShouldEqual, 30)
+			So(counts[2], ShouldEqual, 1)
+		})
+
+		Convey("Ticks before the interval is scheduled are caught up on", func() {
+			scheduled := 0
+			for _, node := range nodes {
+				engine.partition = newPartitionScheduler()
+				engine.partition.Tick(time.Unix(interval+70, 0), engine.execQueue)
+				So(len(engine.execQueue), ShouldEqual, 0)
+				err := scheduleAlertsForPartition(&ScheduleAlertsForPartitionCommand{
+					NodeId:   node,
+					Nodes:    nodes,
+					Interval: interval,
+				})
+				So(err, ShouldBeNil)
+				engine.partition.Tick(time.Unix(interval+119, 0), engine.execQueue)
+				scheduled += len(engine.execQueue)
+				for len(engine.execQueue) > 0 {
+					<-engine.execQueue
+				}
+			}
+			So(scheduled, ShouldEqual, len(rules))
+		})
+
+		Convey("Node that is not a member is refused", func() {
+			err := scheduleAlertsForPartition(&ScheduleAlertsForPartitionCommand{
+				NodeId:   "node4:3000",
+				Nodes:    nodes,
+				Interval: interval,
+			})
+			So(err, ShouldNotBeNil)
+			So(engine.partition.ScheduledUntil(), ShouldEqual, 0)
+		})
+
+		Convey("Empty membership is refused", func() {
+			err := scheduleAlertsForPartition(&ScheduleAlertsForPartitionCommand{
+				NodeId:   "node1:3000",
+				Interval: interval,
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Partition from a stale heartbeat is refused", func() {
+			err := scheduleAlertsForPartition(&ScheduleAlertsForPartitionCommand{
+				NodeId:   "node1:3000",
+				Nodes:    nodes,
+				Interval: interval - 60,
+			})
+			So(err, ShouldEqual, m.ErrStaleFencingToken)
+			So(engine.partition.ScheduledUntil(), ShouldEqual, 0)
+		})
+	})
+}
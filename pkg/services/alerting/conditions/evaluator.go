@@ -2,6 +2,7 @@ package conditions
 
 import (
 	"encoding/json"
+	"math"
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -9,7 +10,7 @@ import (
 )
 
 var (
-	defaultTypes []string = []string{"gt", "lt"}
+	defaultTypes []string = []string{"gt", "lt", "eq", "ne", "gte", "lte"}
 	rangedTypes  []string = []string{"within_range", "outside_range"}
 )
 
@@ -23,9 +24,13 @@ func (e *NoValueEvaluator) Eval(reducedValue null.Float) bool {
 	return reducedValue.Valid == false
 }
 
+// ThresholdEvaluator compares the reduced value with a single threshold. The
+// eq, ne, gte and lte types treat values within Epsilon of the threshold as
+// equal to it.
 type ThresholdEvaluator struct {
 	Type      string
 	Threshold float64
+	Epsilon   float64
 }
 
 func newThresholdEvaluator(typ string, model *simplejson.Json) (*ThresholdEvaluator, error) {
@@ -41,6 +46,15 @@ func newThresholdEvaluator(typ string, model *simplejson.Json) (*ThresholdEvalua
 
 	defaultEval := &ThresholdEvaluator{Type: typ}
 	defaultEval.Threshold, _ = firstParam.Float64()
+
+	if epsilon, exists := model.CheckGet("epsilon"); exists && epsilon.Interface() != nil {
+		value, err := epsilon.Float64()
+		if err != nil || value < 0 {
+			return nil, alerting.ValidationError{Reason: "Evaluator epsilon must be a non-negative number"}
+		}
+		defaultEval.Epsilon = value
+	}
+
 	return defaultEval, nil
 }
 
@@ -54,15 +68,28 @@ func (e *ThresholdEvaluator) Eval(reducedValue null.Float) bool {
 		return reducedValue.Float64 > e.Threshold
 	case "lt":
 		return reducedValue.Float64 < e.Threshold
+	case "eq":
+		return math.Abs(reducedValue.Float64-e.Threshold) <= e.Epsilon
+	case "ne":
+		return math.Abs(reducedValue.Float64-e.Threshold) > e.Epsilon
+	case "gte":
+		return reducedValue.Float64 >= e.Threshold-e.Epsilon
+	case "lte":
+		return reducedValue.Float64 <= e.Threshold+e.Epsilon
 	}
 
 	return false
 }
 
+// RangedEvaluator compares the reduced value with a range. Lower and Upper
+// are the two params in the order they were given and are excluded from the
+// range unless the matching inclusive flag is set.
 type RangedEvaluator struct {
-	Type  string
-	Lower float64
-	Upper float64
+	Type           string
+	Lower          float64
+	Upper          float64
+	LowerInclusive bool
+	UpperInclusive bool
 }
 
 func newRangedEvaluator(typ string, model *simplejson.Json) (*RangedEvaluator, error) {
@@ -76,6 +103,10 @@ func newRangedEvaluator(typ string, model *simplejson.Json) (*RangedEvaluator, e
 		return nil, alerting.ValidationError{Reason: "Evaluator has invalid parameter"}
 	}
 
+	if len(params) < 2 {
+		return nil, alerting.ValidationError{Reason: "Evaluator missing second parameter"}
+	}
+
 	secondParam, ok := params[1].(json.Number)
 	if !ok {
 		return nil, alerting.ValidationError{Reason: "Evaluator has invalid second parameter"}
@@ -84,26 +115,62 @@ func newRangedEvaluator(typ string, model *simplejson.Json) (*RangedEvaluator, e
 	rangedEval := &RangedEvaluator{Type: typ}
 	rangedEval.Lower, _ = firstParam.Float64()
 	rangedEval.Upper, _ = secondParam.Float64()
+
+	var err error
+	if rangedEval.LowerInclusive, err = inclusiveFlag(model, "lowerInclusive"); err != nil {
+		return nil, err
+	}
+	if rangedEval.UpperInclusive, err = inclusiveFlag(model, "upperInclusive"); err != nil {
+		return nil, err
+	}
+
 	return rangedEval, nil
 }
 
+func inclusiveFlag(model *simplejson.Json, key string) (bool, error) {
+	flag, exists := model.CheckGet(key)
+	if !exists || flag.Interface() == nil {
+		return false, nil
+	}
+
+	inclusive, err := flag.Bool()
+	if err != nil {
+		return false, alerting.ValidationError{Reason: "Evaluator " + key + " must be true or false"}
+	}
+	return inclusive, nil
+}
+
 func (e *RangedEvaluator) Eval(reducedValue null.Float) bool {
 	if reducedValue.Valid == false {
 		return false
 	}
 
 	floatValue := reducedValue.Float64
+	aboveLower := isAbove(floatValue, e.Lower, e.LowerInclusive)
+	belowLower := isBelow(floatValue, e.Lower, e.LowerInclusive)
+	aboveUpper := isAbove(floatValue, e.Upper, e.UpperInclusive)
+	belowUpper := isBelow(floatValue, e.Upper, e.UpperInclusive)
 
 	switch e.Type {
 	case "within_range":
-		return (e.Lower < floatValue && e.Upper > floatValue) || (e.Upper < floatValue && e.Lower > floatValue)
+		return (aboveLower && belowUpper) || (belowLower && aboveUpper)
 	case "outside_range":
-		return (e.Upper < floatValue && e.Lower < floatValue) || (e.Upper > floatValue && e.Lower > floatValue)
+		return (aboveUpper && aboveLower) || (belowUpper && belowLower)
 	}
 
 	return false
 }
 
+// isAbove and isBelow report whether value passes the bound, counting the
+// bound itself when it is inclusive.
+func isAbove(value, bound float64, inclusive bool) bool {
+	return value > bound || (inclusive && value == bound)
+}
+
+func isBelow(value, bound float64, inclusive bool) bool {
+	return value < bound || (inclusive && value == bound)
+}
+
 func NewAlertEvaluator(model *simplejson.Json) (AlertEvaluator, error) {
 	typ := model.Get("type").MustString()
 	if typ == "" {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/components/null"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
 )
 
 func evalutorScenario(json string, reducedValue float64, datapoints ...float64) bool {
@@ -30,6 +31,33 @@ func TestEvalutors(t *testing.T) {
 		So(evalutorScenario(`{"type": "lt", "params": [3] }`, 1), ShouldBeTrue)
 	})
 
+	Convey("equal", t, func() {
+		So(evalutorScenario(`{"type": "eq", "params": [200] }`, 200), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "eq", "params": [200] }`, 200.5), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "eq", "params": [1], "epsilon": 0.01 }`, 0.995), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "eq", "params": [1], "epsilon": 0.01 }`, 1.02), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "eq", "params": [1], "epsilon": null }`, 1), ShouldBeTrue)
+	})
+
+	Convey("not equal", t, func() {
+		So(evalutorScenario(`{"type": "ne", "params": [200] }`, 500), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "ne", "params": [200] }`, 200), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "ne", "params": [1], "epsilon": 0.01 }`, 0.995), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "ne", "params": [1], "epsilon": 0.01 }`, 1.02), ShouldBeTrue)
+	})
+
+	Convey("greater than or equal", t, func() {
+		So(evalutorScenario(`{"type": "gte", "params": [3] }`, 3), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "gte", "params": [3] }`, 2.99), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "gte", "params": [3], "epsilon": 0.1 }`, 2.95), ShouldBeTrue)
+	})
+
+	Convey("less than or equal", t, func() {
+		So(evalutorScenario(`{"type": "lte", "params": [3] }`, 3), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "lte", "params": [3] }`, 3.01), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "lte", "params": [3], "epsilon": 0.1 }`, 3.05), ShouldBeTrue)
+	})
+
 	Convey("within_range", t, func() {
 		So(evalutorScenario(`{"type": "within_range", "params": [1, 100] }`, 3), ShouldBeTrue)
 		So(evalutorScenario(`{"type": "within_range", "params": [1, 100] }`, 300), ShouldBeFalse)
@@ -44,6 +72,42 @@ func TestEvalutors(t *testing.T) {
 		So(evalutorScenario(`{"type": "outside_range", "params": [100, 1] }`, 50), ShouldBeFalse)
 	})
 
+	Convey("ranges exclude their bounds by default", t, func() {
+		So(evalutorScenario(`{"type": "within_range", "params": [1, 100] }`, 1), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "within_range", "params": [1, 100] }`, 100), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "outside_range", "params": [1, 100] }`, 1), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "outside_range", "params": [1, 100] }`, 100), ShouldBeFalse)
+	})
+
+	Convey("ranges include bounds flagged as inclusive", t, func() {
+		So(evalutorScenario(`{"type": "within_range", "params": [1, 100], "lowerInclusive": true }`, 1), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "within_range", "params": [1, 100], "lowerInclusive": true }`, 100), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "within_range", "params": [100, 1], "upperInclusive": true }`, 1), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "outside_range", "params": [1, 100], "upperInclusive": true }`, 100), ShouldBeTrue)
+		So(evalutorScenario(`{"type": "outside_range", "params": [1, 100], "upperInclusive": true }`, 1), ShouldBeFalse)
+		So(evalutorScenario(`{"type": "outside_range", "params": [100, 1], "lowerInclusive": true }`, 100), ShouldBeTrue)
+	})
+
+	Convey("invalid evaluators", t, func() {
+		invalid := []string{
+			`{"type": "eq", "params": [] }`,
+			`{"type": "gte", "params": ["a"] }`,
+			`{"type": "ne", "params": [1], "epsilon": -1 }`,
+			`{"type": "eq", "params": [1], "epsilon": "a" }`,
+			`{"type": "within_range", "params": [1] }`,
+			`{"type": "outside_range", "params": [1, 100], "lowerInclusive": "yes" }`,
+			`{"type": "between", "params": [1] }`,
+		}
+
+		for _, model := range invalid {
+			jsonModel, err := simplejson.NewJson([]byte(model))
+			So(err, ShouldBeNil)
+
+			_, err = NewAlertEvaluator(jsonModel)
+			So(err, ShouldHaveSameTypeAs, alerting.ValidationError{})
+		}
+	})
+
 	Convey("no_value", t, func() {
 		Convey("should be false if serie have values", func() {
 			So(evalutorScenario(`{"type": "no_value", "params": [] }`, 50), ShouldBeFalse)
@@ -36,9 +36,13 @@ type AlertQuery struct {
 }
 
 func (c *QueryCondition) Eval(context *alerting.EvalContext) (*alerting.ConditionResult, error) {
-	timeRange := tsdb.NewTimeRange(c.Query.From, c.Query.To)
-
-	seriesList, err := c.executeQuery(context, timeRange)
+	var seriesList tsdb.TimeSeriesSlice
+	var err error
+	if context.Backtest != nil {
+		seriesList, err = c.getBacktestSeries(context)
+	} else {
+		seriesList, err = c.executeQuery(context, tsdb.NewTimeRange(c.Query.From, c.Query.To))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -133,6 +137,76 @@ func (c *QueryCondition) executeQuery(context *alerting.EvalContext, timeRange *
 	return result, nil
 }
 
+type backtestData struct {
+	series tsdb.TimeSeriesSlice
+	err    error
+}
+
+// getBacktestSeries queries the series for the whole backtest once, and
+// returns the points that the query range covers at the current step. The
+// range is relative to now, as not all datasources support absolute ranges.
+func (c *QueryCondition) getBacktestSeries(context *alerting.EvalContext) (tsdb.TimeSeriesSlice, error) {
+	backtest := context.Backtest
+	fromOffset, toOffset, err := c.Query.offsets()
+	if err != nil {
+		return nil, err
+	}
+
+	data, fetched := backtest.Data[c.Index].(*backtestData)
+	if !fetched {
+		now := time.Now()
+		from := fmt.Sprintf("%ds", int64((now.Sub(backtest.From) + fromOffset).Seconds()))
+		to := "now"
+		if toSeconds := int64((now.Sub(backtest.To) + toOffset).Seconds()); toSeconds > 0 {
+			to = fmt.Sprintf("now-%ds", toSeconds)
+		}
+
+		data = &backtestData{}
+		data.series, data.err = c.executeQuery(context, tsdb.NewTimeRange(from, to))
+		backtest.Data[c.Index] = data
+	}
+	if data.err != nil {
+		return nil, data.err
+	}
+
+	start := backtest.At.Add(-fromOffset).UnixNano() / int64(time.Millisecond)
+	end := backtest.At.Add(-toOffset).UnixNano() / int64(time.Millisecond)
+	result := make(tsdb.TimeSeriesSlice, 0, len(data.series))
+	for _, series := range data.series {
+		points := make(tsdb.TimeSeriesPoints, 0)
+		for _, point := range series.Points {
+			if !point[1].Valid {
+				continue
+			}
+			if timestamp := int64(point[1].Float64); timestamp >= start && timestamp <= end {
+				points = append(points, point)
+			}
+		}
+		result = append(result, &tsdb.TimeSeries{Name: series.Name, Points: points, Tags: series.Tags})
+	}
+
+	return result, nil
+}
+
+// offsets returns how long before the evaluation the query range starts and
+// ends.
+func (q *AlertQuery) offsets() (time.Duration, time.Duration, error) {
+	from, err := time.ParseDuration(strings.Replace(q.From, "now-", "", 1))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if q.To == "now" {
+		return from, 0, nil
+	}
+
+	to, err := time.ParseDuration(strings.Replace(q.To, "now-", "", 1))
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
 func (c *QueryCondition) getRequestForAlertRule(datasource *m.DataSource, timeRange *tsdb.TimeRange) *tsdb.Request {
 	req := &tsdb.Request{
 		TimeRange: timeRange,
@@ -169,8 +243,11 @@ func NewQueryCondition(model *simplejson.Json, index int) (*QueryCondition, erro
 
 	condition.Query.DatasourceId = queryJson.Get("datasourceId").MustInt64()
 
-	reducerJson := model.Get("reducer")
-	condition.Reducer = NewSimpleReducer(reducerJson.Get("type").MustString())
+	reducer, err := NewReducer(model.Get("reducer"))
+	if err != nil {
+		return nil, err
+	}
+	condition.Reducer = reducer
 
 	evaluatorJson := model.Get("evaluator")
 	evaluator, err := NewAlertEvaluator(evaluatorJson)
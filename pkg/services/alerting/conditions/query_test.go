@@ -3,6 +3,7 @@ package conditions
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/null"
@@ -137,6 +138,66 @@ func TestQueryCondition(t *testing.T) {
 				})
 			})
 		})
+
+		queryConditionScenario("Given percentile(90) and > 100", func(ctx *queryConditionTestContext) {
+
+			ctx.reducer = `{"type": "percentile", "params": [90]}`
+			ctx.evaluator = `{"type": "gt", "params": [100]}`
+
+			Convey("Can read query reducer", func() {
+				ctx.exec()
+
+				reducer, ok := ctx.condition.Reducer.(*SimpleReducer)
+				So(ok, ShouldBeTrue)
+				So(reducer.Type, ShouldEqual, "percentile")
+				So(reducer.Percentile, ShouldEqual, 90)
+			})
+
+			Convey("Should fire when the 90th percentile is above 100", func() {
+				points := tsdb.NewTimeSeriesPointsFromArgs(10, 0, 20, 1, 30, 2, 40, 3, 50, 4, 60, 5, 70, 6, 80, 7, 90, 8, 500, 9, 600, 10)
+				ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", points)}
+				cr, err := ctx.exec()
+
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestQueryConditionBacktest(t *testing.T) {
+	Convey("when backtesting query condition", t, func() {
+		queryConditionScenario("Given avg() and > 100", func(ctx *queryConditionTestContext) {
+			ctx.reducer = `{"type": "avg"}`
+			ctx.evaluator = `{"type": "gt", "params": [100]}`
+
+			start := time.Now().Add(-time.Hour).Truncate(time.Minute)
+			ms := func(t time.Time) float64 { return float64(t.UnixNano() / int64(time.Millisecond)) }
+			ctx.series = tsdb.TimeSeriesSlice{tsdb.NewTimeSeries("test1", tsdb.TimeSeriesPoints{
+				tsdb.NewTimePoint(null.FloatFrom(120), ms(start.Add(-2*time.Minute))),
+				tsdb.NewTimePoint(null.FloatFrom(20), ms(start.Add(8*time.Minute))),
+			})}
+			ctx.result.Backtest = &alerting.Backtest{From: start, To: start.Add(10 * time.Minute), Data: make(map[int]interface{})}
+
+			Convey("Each step evaluates the points of its query range", func() {
+				ctx.result.Backtest.At = start
+				cr, err := ctx.exec()
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeTrue)
+
+				ctx.series = nil
+				ctx.result.Backtest.At = start.Add(10 * time.Minute)
+				cr, err = ctx.exec()
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+
+				ctx.result.Backtest.At = start.Add(5 * time.Minute)
+				cr, err = ctx.exec()
+				So(err, ShouldBeNil)
+				So(cr.Firing, ShouldBeFalse)
+				So(cr.NoDataFound, ShouldBeTrue)
+			})
+		})
 	})
 }
 
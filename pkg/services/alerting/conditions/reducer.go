@@ -1,11 +1,15 @@
 package conditions
 
 import (
+	"encoding/json"
+	"errors"
 	"math"
-
 	"sort"
+	"strconv"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/alerting"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -14,7 +18,14 @@ type QueryReducer interface {
 }
 
 type SimpleReducer struct {
-	Type string
+	Type       string
+	Percentile float64
+}
+
+var percentileTypes = map[string]float64{
+	"p90": 90,
+	"p95": 95,
+	"p99": 99,
 }
 
 func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
@@ -94,6 +105,53 @@ func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
 				value = (values[(length/2)-1] + values[length/2]) / 2
 			}
 		}
+	case "p90", "p95", "p99", "percentile":
+		var values []float64
+		for _, v := range series.Points {
+			if v[0].Valid {
+				allNull = false
+				values = append(values, v[0].Float64)
+			}
+		}
+		if len(values) >= 1 {
+			sort.Float64s(values)
+			value = percentile(values, s.Percentile)
+		}
+	case "stddev":
+		var values []float64
+		for _, v := range series.Points {
+			if v[0].Valid {
+				allNull = false
+				values = append(values, v[0].Float64)
+			}
+		}
+		if len(values) >= 1 {
+			value = stddev(values)
+		}
+	case "diff", "percent_diff":
+		first, last, valid := firstAndLast(series)
+		if valid {
+			allNull = false
+			value = last - first
+			if s.Type == "percent_diff" {
+				if first == 0 {
+					allNull = true
+				} else {
+					value = value / math.Abs(first) * 100
+				}
+			}
+		}
+	case "count_non_null":
+		for _, v := range series.Points {
+			if v[0].Valid {
+				value += 1
+			}
+		}
+		if value > 0 {
+			allNull = false
+		}
+	case "rate":
+		value, allNull = rate(series)
 	}
 
 	if allNull {
@@ -104,5 +162,103 @@ func (s *SimpleReducer) Reduce(series *tsdb.TimeSeries) null.Float {
 }
 
 func NewSimpleReducer(typ string) *SimpleReducer {
-	return &SimpleReducer{Type: typ}
+	return &SimpleReducer{Type: typ, Percentile: percentileTypes[typ]}
+}
+
+// NewReducer builds the reducer of a condition from its JSON model. The
+// percentile reducer takes the percentile as its first parameter.
+func NewReducer(model *simplejson.Json) (*SimpleReducer, error) {
+	reducer := NewSimpleReducer(model.Get("type").MustString())
+	if reducer.Type != "percentile" {
+		return reducer, nil
+	}
+
+	params := model.Get("params").MustArray()
+	if len(params) == 0 {
+		return nil, alerting.ValidationError{Reason: "Reducer missing percentile parameter"}
+	}
+
+	var err error
+	switch param := params[0].(type) {
+	case json.Number:
+		reducer.Percentile, err = param.Float64()
+	case string:
+		reducer.Percentile, err = strconv.ParseFloat(param, 64)
+	default:
+		err = errors.New("not a number")
+	}
+	if err != nil || reducer.Percentile <= 0 || reducer.Percentile > 100 {
+		return nil, alerting.ValidationError{Reason: "Reducer percentile must be a number between 0 and 100"}
+	}
+	return reducer, nil
+}
+
+// percentile interpolates linearly between the closest ranks of the sorted
+// values, so the 50th percentile is the median.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// stddev is the population standard deviation of the values.
+func stddev(values []float64) float64 {
+	mean := float64(0)
+	for _, v := range values {
+		mean += v
+	}
+	mean = mean / float64(len(values))
+
+	variance := float64(0)
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(values)))
+}
+
+func firstAndLast(series *tsdb.TimeSeries) (first float64, last float64, valid bool) {
+	points := series.Points
+	for i := 0; i < len(points); i++ {
+		if points[i][0].Valid {
+			first = points[i][0].Float64
+			valid = true
+			break
+		}
+	}
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i][0].Valid {
+			last = points[i][0].Float64
+			break
+		}
+	}
+	return first, last, valid
+}
+
+// rate is the per second increase of a counter between its first and last
+// value. A decrease is taken as a counter reset, so the value after it counts
+// as the increase. It needs two values at different times.
+func rate(series *tsdb.TimeSeries) (float64, bool) {
+	var increase, prev, firstTime, lastTime float64
+	count := 0
+	for _, point := range series.Points {
+		if !point[0].Valid || !point[1].Valid {
+			continue
+		}
+		if count == 0 {
+			firstTime = point[1].Float64
+		} else if point[0].Float64 >= prev {
+			increase += point[0].Float64 - prev
+		} else {
+			increase += point[0].Float64
+		}
+		prev = point[0].Float64
+		lastTime = point[1].Float64
+		count++
+	}
+	if count < 2 || lastTime <= firstTime {
+		return 0, true
+	}
+	// timestamps are in milliseconds
+	return increase / ((lastTime - firstTime) / 1000), false
 }
@@ -6,6 +6,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/tsdb"
 )
 
@@ -83,6 +84,89 @@ func TestSimpleReducer(t *testing.T) {
 	})
 }
 
+func TestReducers(t *testing.T) {
+	Convey("Test reducers on series with nulls", t, func() {
+		n := null.FloatFromPtr(nil)
+		v := null.FloatFrom
+
+		tests := []struct {
+			name     string
+			reducer  *SimpleReducer
+			points   []null.Float // one point every 10 seconds
+			expected null.Float
+		}{
+			{"p90", NewSimpleReducer("p90"), []null.Float{v(1), v(2), n, v(3), v(4), v(5), v(6), v(7), v(8), v(9), v(10), v(11)}, v(10)},
+			{"p95 of one value", NewSimpleReducer("p95"), []null.Float{n, v(4), n}, v(4)},
+			{"p99 of only nulls", NewSimpleReducer("p99"), []null.Float{n, n}, n},
+			{"p99 of empty series", NewSimpleReducer("p99"), []null.Float{}, n},
+			{"percentile 50 is the median", &SimpleReducer{Type: "percentile", Percentile: 50}, []null.Float{v(1), v(2), v(4), v(3000)}, v(3)},
+			{"percentile 100 is the max", &SimpleReducer{Type: "percentile", Percentile: 100}, []null.Float{v(3), n, v(7), v(1)}, v(7)},
+			{"stddev", NewSimpleReducer("stddev"), []null.Float{v(2), v(4), v(4), n, v(4), v(5), v(5), v(7), v(9)}, v(2)},
+			{"stddev of one value", NewSimpleReducer("stddev"), []null.Float{v(5)}, v(0)},
+			{"stddev of only nulls", NewSimpleReducer("stddev"), []null.Float{n}, n},
+			{"diff", NewSimpleReducer("diff"), []null.Float{n, v(30), v(10), v(40), n}, v(10)},
+			{"diff of one value", NewSimpleReducer("diff"), []null.Float{n, v(30)}, v(0)},
+			{"diff of only nulls", NewSimpleReducer("diff"), []null.Float{n, n}, n},
+			{"diff of empty series", NewSimpleReducer("diff"), []null.Float{}, n},
+			{"percent_diff", NewSimpleReducer("percent_diff"), []null.Float{n, v(40), v(10), v(30)}, v(-25)},
+			{"percent_diff from negative value", NewSimpleReducer("percent_diff"), []null.Float{v(-20), v(-10)}, v(50)},
+			{"percent_diff from zero", NewSimpleReducer("percent_diff"), []null.Float{v(0), v(10)}, n},
+			{"count_non_null", NewSimpleReducer("count_non_null"), []null.Float{v(1), n, v(0), n}, v(2)},
+			{"count_non_null of only nulls", NewSimpleReducer("count_non_null"), []null.Float{n, n}, n},
+			{"rate", NewSimpleReducer("rate"), []null.Float{v(10), v(20), n, v(50)}, v(40.0 / 30)},
+			{"rate with counter reset", NewSimpleReducer("rate"), []null.Float{v(10), v(30), v(5), v(15)}, v(35.0 / 30)},
+			{"rate of one value", NewSimpleReducer("rate"), []null.Float{n, v(10)}, n},
+			{"rate of empty series", NewSimpleReducer("rate"), []null.Float{}, n},
+		}
+
+		for _, test := range tests {
+			series := &tsdb.TimeSeries{Name: "test time serie"}
+			for i, point := range test.points {
+				series.Points = append(series.Points, tsdb.NewTimePoint(point, float64(1493233560000+i*10000)))
+			}
+
+			result := test.reducer.Reduce(series)
+			So(result.Valid, ShouldEqual, test.expected.Valid)
+			if test.expected.Valid {
+				So(result.Float64, ShouldAlmostEqual, test.expected.Float64, 0.000001)
+			}
+		}
+	})
+
+	Convey("Test reducer from json model", t, func() {
+		Convey("p95 needs no parameter", func() {
+			reducer, err := NewReducer(simplejson.NewFromAny(map[string]interface{}{"type": "p95"}))
+			So(err, ShouldBeNil)
+			So(reducer.Percentile, ShouldEqual, 95)
+		})
+
+		Convey("percentile reads the parameter", func() {
+			json, _ := simplejson.NewJson([]byte(`{"type": "percentile", "params": [99.9]}`))
+			reducer, err := NewReducer(json)
+			So(err, ShouldBeNil)
+			So(reducer.Percentile, ShouldEqual, 99.9)
+
+			json, _ = simplejson.NewJson([]byte(`{"type": "percentile", "params": ["75"]}`))
+			reducer, err = NewReducer(json)
+			So(err, ShouldBeNil)
+			So(reducer.Percentile, ShouldEqual, 75)
+		})
+
+		Convey("percentile without a valid parameter is refused", func() {
+			for _, model := range []string{
+				`{"type": "percentile", "params": []}`,
+				`{"type": "percentile", "params": [0]}`,
+				`{"type": "percentile", "params": [101]}`,
+				`{"type": "percentile", "params": ["high"]}`,
+			} {
+				json, _ := simplejson.NewJson([]byte(model))
+				_, err := NewReducer(json)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
 func testReducer(typ string, datapoints ...float64) float64 {
 	reducer := NewSimpleReducer(typ)
 	series := &tsdb.TimeSeries{
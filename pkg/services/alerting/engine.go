@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/setting"
 	"golang.org/x/sync/errgroup"
 )
@@ -16,6 +19,7 @@ type Engine struct {
 	clock         clock.Clock
 	ticker        *Ticker
 	scheduler     Scheduler
+	partition     *partitionScheduler
 	evalHandler   EvalHandler
 	ruleReader    RuleReader
 	log           log.Logger
@@ -34,6 +38,7 @@ func NewEngine() (*Engine, error) {
 		ticker:        NewTicker(time.Now(), time.Second*0, clock.New()),
 		execQueue:     make(chan *Job, 1000),
 		scheduler:     NewScheduler(),
+		partition:     newPartitionScheduler(),
 		evalHandler:   NewEvalHandler(),
 		ruleReader:    NewRuleReader(),
 		log:           log.New("alerting.engine"),
@@ -47,7 +52,9 @@ func (e *Engine) Run(ctx context.Context) error {
 	e.log.Info("Initializing Alerting")
 
 	alertGroup, ctx := errgroup.WithContext(ctx)
-	if !setting.ClusteringEnabled {
+	if setting.ClusteringEnabled {
+		alertGroup.Go(func() error { return e.clusteredAlertingTicker(ctx) })
+	} else {
 		alertGroup.Go(func() error { return e.alertingTicker(ctx) })
 	}
 	alertGroup.Go(func() error { return e.runJobDispatcher(ctx) })
@@ -83,6 +90,25 @@ func (e *Engine) alertingTicker(grafanaCtx context.Context) error {
 	}
 }
 
+// clusteredAlertingTicker runs the rules the cluster manager assigned to this
+// node. The rules are updated every interval by ScheduleAlertsForPartitionCommand.
+func (e *Engine) clusteredAlertingTicker(grafanaCtx context.Context) error {
+	defer func() {
+		if err := recover(); err != nil {
+			e.log.Error("Scheduler Panic: stopping clusteredAlertingTicker", "error", err, "stack", log.Stack(1))
+		}
+	}()
+
+	for {
+		select {
+		case <-grafanaCtx.Done():
+			return grafanaCtx.Err()
+		case tick := <-e.ticker.C:
+			e.partition.Tick(tick, e.execQueue)
+		}
+	}
+}
+
 func (e *Engine) runJobDispatcher(grafanaCtx context.Context) error {
 	dispatcherGroup, alertCtx := errgroup.WithContext(grafanaCtx)
 
@@ -108,10 +134,16 @@ func (e *Engine) processJob(grafanaCtx context.Context, job *Job) error {
 		}
 	}()
 
+	execution, claimed := e.claimExecution(job)
+	if !claimed {
+		return nil
+	}
+
 	alertCtx, cancelFn := context.WithTimeout(context.Background(), alertTimeout)
 
 	job.Running = true
 	evalContext := NewEvalContext(alertCtx, job.Rule)
+	evalContext.NodeId = job.NodeId
 
 	done := make(chan struct{})
 
@@ -141,7 +173,75 @@ func (e *Engine) processJob(grafanaCtx context.Context, job *Job) error {
 	}
 
 	e.log.Debug("Job Execution completed", "timeMs", evalContext.GetDurationMs(), "alertId", evalContext.Rule.Id, "name", evalContext.Rule.Name, "firing", evalContext.Firing)
+	e.finishExecution(execution, evalContext, err)
+	if job.NodeId != "" {
+		if err != nil || evalContext.Error != nil {
+			metrics.M_Clustering_Failed_Jobs.With(job.Partition).Inc(1)
+		} else {
+			metrics.M_Clustering_Executed_Jobs.With(job.Partition).Inc(1)
+		}
+	}
 	job.Running = false
 	cancelFn()
 	return err
 }
+
+// claimExecution claims the interval the job is due for in the alert
+// execution ledger, so that only one node in the cluster evaluates it. It
+// returns false when the interval was claimed before.
+func (e *Engine) claimExecution(job *Job) (*m.AlertExecution, bool) {
+	if !setting.ClusteringEnabled || job.ScheduledAt == 0 {
+		return nil, true
+	}
+	cmd := &m.ClaimAlertExecutionCommand{
+		AlertId:     job.Rule.Id,
+		OrgId:       job.Rule.OrgId,
+		ScheduledAt: job.ScheduledAt,
+		NodeId:      job.NodeId,
+		StartedAt:   time.Now().Unix(),
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		if err == m.ErrAlertExecutionClaimed {
+			e.log.Debug("Alert execution already claimed", "alertId", job.Rule.Id, "scheduledAt", job.ScheduledAt)
+			return nil, false
+		}
+		// a ledger failure must not stop the alert from being evaluated
+		e.log.Error("Failed to claim alert execution", "alertId", job.Rule.Id, "scheduledAt", job.ScheduledAt, "error", err)
+		return nil, true
+	}
+	return cmd.Result, true
+}
+
+func (e *Engine) finishExecution(execution *m.AlertExecution, evalContext *EvalContext, err error) {
+	if execution == nil {
+		return
+	}
+	finishedAt := time.Now()
+	cmd := &m.FinishAlertExecutionCommand{
+		Id:         execution.Id,
+		FinishedAt: finishedAt.Unix(),
+		DurationMs: int64(finishedAt.Sub(evalContext.StartTime) / time.Millisecond),
+		Outcome:    string(evalContext.Rule.State),
+	}
+	switch {
+	case err == context.Canceled:
+		// grafana shut down before the evaluation finished
+		cmd.Outcome = m.ALERT_EXECUTION_OUTCOME_CANCELLED
+		cmd.Error = err.Error()
+	case err != nil:
+		cmd.Outcome = m.ALERT_EXECUTION_OUTCOME_TIMEOUT
+		cmd.Error = err.Error()
+	case evalContext.Ctx != nil && evalContext.Ctx.Err() == context.DeadlineExceeded:
+		cmd.Outcome = m.ALERT_EXECUTION_OUTCOME_TIMEOUT
+		cmd.Error = evalContext.Ctx.Err().Error()
+		if evalContext.Error != nil {
+			cmd.Error = evalContext.Error.Error()
+		}
+	case evalContext.Error != nil:
+		cmd.Outcome = m.ALERT_EXECUTION_OUTCOME_ERROR
+		cmd.Error = evalContext.Error.Error()
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		e.log.Error("Failed to record alert execution", "alertId", execution.AlertId, "scheduledAt", execution.ScheduledAt, "error", err)
+	}
+}
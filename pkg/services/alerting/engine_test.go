@@ -0,0 +1,90 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEngineAlertExecutionLedger(t *testing.T) {
+	Convey("Test alert execution claims", t, func() {
+		e := &Engine{log: log.New("alerting.engine")}
+		claims := make(map[int64]string)
+		bus.AddHandler("test", func(cmd *m.ClaimAlertExecutionCommand) error {
+			if _, exists := claims[cmd.ScheduledAt]; exists {
+				return m.ErrAlertExecutionClaimed
+			}
+			claims[cmd.ScheduledAt] = cmd.NodeId
+			cmd.Result = &m.AlertExecution{Id: int64(len(claims)), AlertId: cmd.AlertId, ScheduledAt: cmd.ScheduledAt}
+			return nil
+		})
+		prevClusteringEnabled := setting.ClusteringEnabled
+		defer func() { setting.ClusteringEnabled = prevClusteringEnabled }()
+
+		Convey("Interval is evaluated by the node that claims it first", func() {
+			setting.ClusteringEnabled = true
+			execution, claimed := e.claimExecution(&Job{Rule: &Rule{Id: 1}, ScheduledAt: 1493233560, NodeId: "node1:3000"})
+			So(claimed, ShouldBeTrue)
+			So(execution.ScheduledAt, ShouldEqual, 1493233560)
+			So(claims[1493233560], ShouldEqual, "node1:3000")
+
+			_, claimed = e.claimExecution(&Job{Rule: &Rule{Id: 1}, ScheduledAt: 1493233560, NodeId: "node2:3000"})
+			So(claimed, ShouldBeFalse)
+		})
+
+		Convey("Ledger is not used without clustering", func() {
+			setting.ClusteringEnabled = false
+			execution, claimed := e.claimExecution(&Job{Rule: &Rule{Id: 1}, ScheduledAt: 1493233560})
+			So(claimed, ShouldBeTrue)
+			So(execution, ShouldBeNil)
+			So(len(claims), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestEngineAlertExecutionOutcome(t *testing.T) {
+	Convey("Test alert execution outcomes", t, func() {
+		e := &Engine{log: log.New("alerting.engine")}
+		var finished *m.FinishAlertExecutionCommand
+		bus.AddHandler("test", func(cmd *m.FinishAlertExecutionCommand) error {
+			finished = cmd
+			return nil
+		})
+		execution := &m.AlertExecution{Id: 1, AlertId: 1, ScheduledAt: 1493233560}
+		evalContext := NewEvalContext(context.Background(), &Rule{Id: 1, State: m.AlertStateOK})
+
+		Convey("Outcome is the alert state", func() {
+			e.finishExecution(execution, evalContext, nil)
+			So(finished.Outcome, ShouldEqual, "ok")
+		})
+
+		Convey("Evaluation stopped by a shutdown is cancelled", func() {
+			e.finishExecution(execution, evalContext, context.Canceled)
+			So(finished.Outcome, ShouldEqual, m.ALERT_EXECUTION_OUTCOME_CANCELLED)
+		})
+
+		Convey("Evaluation past its deadline is a timeout", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+			<-ctx.Done()
+			evalContext.Ctx = ctx
+			evalContext.Error = errors.New("query timed out")
+			e.finishExecution(execution, evalContext, nil)
+			So(finished.Outcome, ShouldEqual, m.ALERT_EXECUTION_OUTCOME_TIMEOUT)
+			So(finished.Error, ShouldEqual, "query timed out")
+		})
+
+		Convey("Failed evaluation is an error", func() {
+			evalContext.Error = errors.New("no datasource")
+			e.finishExecution(execution, evalContext, nil)
+			So(finished.Outcome, ShouldEqual, m.ALERT_EXECUTION_OUTCOME_ERROR)
+		})
+	})
+}
@@ -3,6 +3,8 @@ package alerting
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -27,19 +29,35 @@ type EvalContext struct {
 	ImageOnDiskPath string
 	NoDataFound     bool
 	PrevAlertState  m.AlertStateType
+	NodeId          string
+
+	// series that started or stopped alerting in this evaluation
+	StartedInstances  []*EvalMatch
+	ResolvedInstances []*EvalMatch
+
+	silences []*m.AlertSilence
+
+	// set when the rule is evaluated at a past time by a backtest
+	Backtest *Backtest
+
+	// the evaluations of a group of notifications that are sent combined
+	Group    []*EvalContext
+	GroupKey string
 
 	Ctx context.Context
 }
 
 func NewEvalContext(alertCtx context.Context, rule *Rule) *EvalContext {
 	return &EvalContext{
-		Ctx:            alertCtx,
-		StartTime:      time.Now(),
-		Rule:           rule,
-		Logs:           make([]*ResultLogEntry, 0),
-		EvalMatches:    make([]*EvalMatch, 0),
-		log:            log.New("alerting.evalContext"),
-		PrevAlertState: rule.State,
+		Ctx:               alertCtx,
+		StartTime:         time.Now(),
+		Rule:              rule,
+		Logs:              make([]*ResultLogEntry, 0),
+		EvalMatches:       make([]*EvalMatch, 0),
+		StartedInstances:  make([]*EvalMatch, 0),
+		ResolvedInstances: make([]*EvalMatch, 0),
+		log:               log.New("alerting.evalContext"),
+		PrevAlertState:    rule.State,
 	}
 }
 
@@ -66,6 +84,11 @@ func (c *EvalContext) GetStateModel() *StateDescription {
 			Color: "#D63232",
 			Text:  "Alerting",
 		}
+	case m.AlertStatePending:
+		return &StateDescription{
+			Color: "#FF9830",
+			Text:  "Pending",
+		}
 	default:
 		panic("Unknown rule state " + c.Rule.State)
 	}
@@ -76,6 +99,10 @@ func (c *EvalContext) ShouldUpdateAlertState() bool {
 }
 
 func (c *EvalContext) ShouldSendNotification() bool {
+	if c.Rule.State == m.AlertStatePending {
+		return false
+	}
+
 	if (c.PrevAlertState == m.AlertStatePending) && (c.Rule.State == m.AlertStateOK) {
 		return false
 	}
@@ -87,8 +114,23 @@ func (a *EvalContext) GetDurationMs() float64 {
 	return float64(a.EndTime.Nanosecond()-a.StartTime.Nanosecond()) / float64(1000000)
 }
 
+// GetNotificationTitle returns the state and the rule name rendered as a
+// template.
 func (c *EvalContext) GetNotificationTitle() string {
-	return "[" + c.GetStateModel().Text + "] " + c.Rule.Name
+	return "[" + c.GetStateModel().Text + "] " + c.GetRuleName()
+}
+
+// GetInstancesText lists the series that started or stopped alerting, one
+// per line, for notifiers that send plain text.
+func (c *EvalContext) GetInstancesText() string {
+	lines := make([]string, 0)
+	for _, match := range c.StartedInstances {
+		lines = append(lines, "Started: "+InstanceName(match.Metric, match.Tags))
+	}
+	for _, match := range c.ResolvedInstances {
+		lines = append(lines, "Resolved: "+InstanceName(match.Metric, match.Tags))
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (c *EvalContext) GetDashboardSlug() (string, error) {
@@ -105,13 +147,48 @@ func (c *EvalContext) GetDashboardSlug() (string, error) {
 	return c.dashboardSlug, nil
 }
 
+// GetSilences returns the silences that suppress the notifications of this
+// evaluation. Test runs are never silenced.
+func (c *EvalContext) GetSilences() ([]*m.AlertSilence, error) {
+	if c.silences != nil || c.IsTestRun {
+		return c.silences, nil
+	}
+
+	query := &m.GetActiveAlertSilencesQuery{
+		OrgId:       c.Rule.OrgId,
+		AlertId:     c.Rule.Id,
+		DashboardId: c.Rule.DashboardId,
+		RuleName:    c.Rule.Name,
+		Now:         c.StartTime,
+	}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	c.silences = query.Result
+	return c.silences, nil
+}
+
+// GetAlertKey identifies the alert in services that deduplicate alerts, so a
+// later notification of the same rule updates the same alert.
+func (c *EvalContext) GetAlertKey() string {
+	return "alertId-" + strconv.FormatInt(c.Rule.Id, 10)
+}
+
 func (c *EvalContext) GetRuleUrl() (string, error) {
 	if c.IsTestRun {
 		return setting.AppUrl, nil
 	}
 
+	// standalone rules have no panel to link to
+	if c.Rule.DashboardId == 0 {
+		return setting.AppUrl + "alerting/list", nil
+	}
+
 	if slug, err := c.GetDashboardSlug(); err != nil {
 		return "", err
+	} else if c.Group != nil {
+		return fmt.Sprintf("%sdashboard/db/%s?orgId=%d", setting.AppUrl, slug, c.Rule.OrgId), nil
 	} else {
 		ruleUrl := fmt.Sprintf("%sdashboard/db/%s?fullscreen&edit&tab=alert&panelId=%d&orgId=%d", setting.AppUrl, slug, c.Rule.PanelId, c.Rule.OrgId)
 		return ruleUrl, nil
@@ -29,6 +29,12 @@ func TestAlertingEvalContext(t *testing.T) {
 			})
 		})
 
+		Convey("Has a state model for pending", func() {
+			ctx.Rule.State = models.AlertStatePending
+
+			So(ctx.GetStateModel().Text, ShouldEqual, "Pending")
+		})
+
 		Convey("Should send notifications", func() {
 			Convey("pending -> ok", func() {
 				ctx.PrevAlertState = models.AlertStatePending
@@ -43,6 +49,13 @@ func TestAlertingEvalContext(t *testing.T) {
 
 				So(ctx.ShouldSendNotification(), ShouldBeTrue)
 			})
+
+			Convey("ok -> pending", func() {
+				ctx.PrevAlertState = models.AlertStateOK
+				ctx.Rule.State = models.AlertStatePending
+
+				So(ctx.ShouldSendNotification(), ShouldBeFalse)
+			})
 		})
 	})
 }
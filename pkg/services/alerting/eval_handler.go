@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/log"
 	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/models"
@@ -63,8 +64,10 @@ func (e *DefaultEvalHandler) Eval(context *EvalContext) {
 	context.EndTime = time.Now()
 	context.Rule.State = e.getNewState(context)
 
-	elapsedTime := context.EndTime.Sub(context.StartTime) / time.Millisecond
-	metrics.M_Alerting_Execution_Time.Update(elapsedTime)
+	if context.Backtest == nil {
+		elapsedTime := context.EndTime.Sub(context.StartTime) / time.Millisecond
+		metrics.M_Alerting_Execution_Time.Update(elapsedTime)
+	}
 }
 
 // This should be move into evalContext once its been refactored.
@@ -82,7 +85,7 @@ func (handler *DefaultEvalHandler) getNewState(evalContext *EvalContext) models.
 			return evalContext.Rule.ExecutionErrorState.ToAlertState()
 		}
 	} else if evalContext.Firing {
-		return models.AlertStateAlerting
+		return handler.getFiringState(evalContext)
 	} else if evalContext.NoDataFound {
 		handler.log.Info("Alert Rule returned no data",
 			"ruleId", evalContext.Rule.Id,
@@ -98,3 +101,37 @@ func (handler *DefaultEvalHandler) getNewState(evalContext *EvalContext) models.
 
 	return models.AlertStateOK
 }
+
+// getFiringState keeps a firing rule in pending until its conditions have
+// fired for the for duration of the rule. The state and the time it was
+// entered are read from the stored alert, so a restart or a move to another
+// cluster node does not reset the timer. A backtest keeps them in the rule.
+func (handler *DefaultEvalHandler) getFiringState(evalContext *EvalContext) models.AlertStateType {
+	if evalContext.Rule.For == 0 || evalContext.IsTestRun {
+		return models.AlertStateAlerting
+	}
+
+	state, since, now := evalContext.PrevAlertState, evalContext.Rule.NewStateDate, evalContext.EndTime
+	if evalContext.Backtest != nil {
+		now = evalContext.Backtest.At
+	} else {
+		query := &models.GetAlertByIdQuery{Id: evalContext.Rule.Id}
+		if err := bus.Dispatch(query); err != nil {
+			handler.log.Error("Failed to get stored alert state", "ruleId", evalContext.Rule.Id, "error", err)
+		} else {
+			state, since = query.Result.State, query.Result.NewStateDate
+			evalContext.PrevAlertState = state
+		}
+	}
+
+	switch state {
+	case models.AlertStateAlerting:
+		return models.AlertStateAlerting
+	case models.AlertStatePending:
+		if now.Sub(since) >= evalContext.Rule.For {
+			return models.AlertStateAlerting
+		}
+	}
+
+	return models.AlertStatePending
+}
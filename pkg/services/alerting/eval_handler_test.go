@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -234,5 +236,51 @@ func TestAlertingEvaluationHandler(t *testing.T) {
 				})
 			})
 		})
+
+		Convey("EvalHandler keeps firing rules with a for duration pending", func() {
+			stored := &models.Alert{Id: 1, State: models.AlertStateOK}
+			bus.AddHandler("test", func(query *models.GetAlertByIdQuery) error {
+				query.Result = stored
+				return nil
+			})
+
+			ctx := NewEvalContext(context.TODO(), &Rule{Id: 1, For: 5 * time.Minute, Conditions: []Condition{&conditionStub{firing: true}}})
+			ctx.Firing = true
+			ctx.EndTime = time.Now()
+
+			Convey("ok -> pending", func() {
+				So(handler.getNewState(ctx), ShouldEqual, models.AlertStatePending)
+			})
+
+			Convey("pending -> pending while the for duration has not passed", func() {
+				stored.State = models.AlertStatePending
+				stored.NewStateDate = ctx.EndTime.Add(-4 * time.Minute)
+
+				So(handler.getNewState(ctx), ShouldEqual, models.AlertStatePending)
+			})
+
+			Convey("pending -> alerting once the for duration has passed", func() {
+				stored.State = models.AlertStatePending
+				stored.NewStateDate = ctx.EndTime.Add(-5 * time.Minute)
+
+				So(handler.getNewState(ctx), ShouldEqual, models.AlertStateAlerting)
+			})
+
+			Convey("uses the stored state rather than the state of the rule", func() {
+				ctx.PrevAlertState = models.AlertStateOK
+				stored.State = models.AlertStatePending
+				stored.NewStateDate = ctx.EndTime.Add(-10 * time.Minute)
+
+				So(handler.getNewState(ctx), ShouldEqual, models.AlertStateAlerting)
+				So(ctx.PrevAlertState, ShouldEqual, models.AlertStatePending)
+			})
+
+			Convey("alerting -> alerting", func() {
+				stored.State = models.AlertStateAlerting
+				stored.NewStateDate = ctx.EndTime
+
+				So(handler.getNewState(ctx), ShouldEqual, models.AlertStateAlerting)
+			})
+		})
 	})
 }
@@ -26,8 +26,14 @@ func NewDashAlertExtractor(dash *m.Dashboard, orgId int64) *DashAlertExtractor {
 }
 
 func (e *DashAlertExtractor) lookupDatasourceId(dsName string) (*m.DataSource, error) {
+	return lookupDatasource(e.OrgId, dsName)
+}
+
+// lookupDatasource returns the datasource of the org with the name, or the
+// default datasource if the name is empty.
+func lookupDatasource(orgId int64, dsName string) (*m.DataSource, error) {
 	if dsName == "" {
-		query := &m.GetDataSourcesQuery{OrgId: e.OrgId}
+		query := &m.GetDataSourcesQuery{OrgId: orgId}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		} else {
@@ -38,7 +44,7 @@ func (e *DashAlertExtractor) lookupDatasourceId(dsName string) (*m.DataSource, e
 			}
 		}
 	} else {
-		query := &m.GetDataSourceByNameQuery{Name: dsName, OrgId: e.OrgId}
+		query := &m.GetDataSourceByNameQuery{Name: dsName, OrgId: orgId}
 		if err := bus.Dispatch(query); err != nil {
 			return nil, err
 		} else {
@@ -108,6 +114,7 @@ func (e *DashAlertExtractor) GetAlerts() ([]*m.Alert, error) {
 				Name:        jsonAlert.Get("name").MustString(),
 				Handler:     jsonAlert.Get("handler").MustInt64(),
 				Message:     jsonAlert.Get("message").MustString(),
+				Severity:    jsonAlert.Get("severity").MustString(),
 				Frequency:   frequency,
 			}
 
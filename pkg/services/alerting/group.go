@@ -0,0 +1,224 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+var ErrNotificationGroupSettingsInvalid = errors.New("Alert notification group wait and group interval must be durations like 30s or 5m")
+
+const (
+	GROUP_BY_DASHBOARD = "dashboard"
+	GROUP_BY_SEVERITY  = "severity"
+	GROUP_BY_STATE     = "state"
+	GROUP_BY_RULE      = "rule"
+	GROUP_BY_LABEL     = "labels."
+)
+
+// NotificationGroupSettings are read from the settings of a notification
+// channel. Notifications are only grouped when Wait is set.
+type NotificationGroupSettings struct {
+	Wait     time.Duration // how long the first notification of a group waits for others
+	Interval time.Duration // how long after a group was sent the next one of the same group is sent
+	By       []string
+}
+
+// NewNotificationGroupSettings reads groupWait, groupInterval and groupBy, a
+// comma separated list of dashboard, severity, state, rule and labels.<name>.
+// Notifications are grouped by dashboard by default.
+func NewNotificationGroupSettings(settings *simplejson.Json) (NotificationGroupSettings, error) {
+	result := NotificationGroupSettings{}
+	if settings == nil {
+		return result, nil
+	}
+
+	if wait := settings.Get("groupWait").MustString(); wait != "" {
+		duration, err := time.ParseDuration(wait)
+		if err != nil || duration < 0 {
+			return result, ErrNotificationGroupSettingsInvalid
+		}
+		result.Wait = duration
+	}
+
+	result.Interval = result.Wait
+	if interval := settings.Get("groupInterval").MustString(); interval != "" {
+		duration, err := time.ParseDuration(interval)
+		if err != nil || duration < 0 {
+			return result, ErrNotificationGroupSettingsInvalid
+		}
+		result.Interval = duration
+	}
+
+	for _, key := range strings.Split(settings.Get("groupBy").MustString(GROUP_BY_DASHBOARD), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			result.By = append(result.By, key)
+		}
+	}
+
+	return result, nil
+}
+
+// notificationGroupKey tells apart the groups of the notifications of one
+// notification channel.
+func notificationGroupKey(evalContext *EvalContext, by []string) string {
+	parts := make([]string, 0, len(by))
+	for _, key := range by {
+		value := ""
+		switch {
+		case key == GROUP_BY_DASHBOARD:
+			value = fmt.Sprint(evalContext.Rule.DashboardId)
+		case key == GROUP_BY_SEVERITY:
+			value = evalContext.Rule.Severity
+		case key == GROUP_BY_STATE:
+			value = string(evalContext.Rule.State)
+		case key == GROUP_BY_RULE:
+			value = fmt.Sprint(evalContext.Rule.Id)
+		case strings.HasPrefix(key, GROUP_BY_LABEL):
+			value = evalContext.Rule.Labels[strings.TrimPrefix(key, GROUP_BY_LABEL)]
+		}
+		parts = append(parts, key+"="+value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// newGroupEvalContext combines the evaluations of a group into one, which
+// notifiers send like the evaluation of a single rule. The title and message
+// list the alerts of the group.
+func newGroupEvalContext(groupKey string, members []*EvalContext) *EvalContext {
+	if len(members) == 1 {
+		// the evaluation of the rule has finished, and its context with it
+		single := *members[0]
+		single.Ctx = context.Background()
+		return &single
+	}
+
+	first := members[0].Rule
+	rule := &Rule{
+		OrgId:       first.OrgId,
+		DashboardId: first.DashboardId,
+		Name:        fmt.Sprintf("%d alerts", len(members)),
+		State:       m.AlertStateOK,
+	}
+
+	evalContext := NewEvalContext(context.Background(), rule)
+	evalContext.Group = members
+	evalContext.GroupKey = groupKey
+	evalContext.PrevAlertState = m.AlertStateOK
+
+	errs := make([]string, 0)
+	for _, member := range members {
+		if member.Rule.DashboardId != rule.DashboardId {
+			rule.DashboardId = 0
+		}
+		if groupStateOrder[member.Rule.State] > groupStateOrder[rule.State] {
+			rule.State = member.Rule.State
+		}
+		evalContext.Firing = evalContext.Firing || member.Firing
+		evalContext.NoDataFound = evalContext.NoDataFound || member.NoDataFound
+		evalContext.EvalMatches = append(evalContext.EvalMatches, member.EvalMatches...)
+		evalContext.StartedInstances = append(evalContext.StartedInstances, member.StartedInstances...)
+		evalContext.ResolvedInstances = append(evalContext.ResolvedInstances, member.ResolvedInstances...)
+		if evalContext.ImagePublicUrl == "" {
+			evalContext.ImagePublicUrl = member.ImagePublicUrl
+		}
+		if member.Error != nil {
+			errs = append(errs, member.GetRuleName()+": "+member.Error.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		evalContext.Error = errors.New(strings.Join(errs, "\n"))
+	}
+	return evalContext
+}
+
+// the state of a group is the most severe state of its alerts
+var groupStateOrder = map[m.AlertStateType]int{
+	m.AlertStateOK:       0,
+	m.AlertStatePaused:   1,
+	m.AlertStatePending:  2,
+	m.AlertStateNoData:   3,
+	m.AlertStateAlerting: 4,
+}
+
+type notificationGroup struct {
+	notifier Notifier
+	key      string
+	members  []*EvalContext
+}
+
+// notificationGrouper buffers the notifications of the channels that group
+// them, and sends each group when its wait is over.
+type notificationGrouper struct {
+	sync.Mutex
+	groups map[string]*notificationGroup
+	next   map[string]time.Time // earliest time the group can be sent again
+	send   func(group *notificationGroup)
+	log    log.Logger
+}
+
+func newNotificationGrouper(send func(group *notificationGroup)) *notificationGrouper {
+	return &notificationGrouper{
+		groups: make(map[string]*notificationGroup),
+		next:   make(map[string]time.Time),
+		send:   send,
+		log:    log.New("alerting.notificationGrouper"),
+	}
+}
+
+func (g *notificationGrouper) add(notifier Notifier, evalContext *EvalContext) {
+	settings := notifier.GetGroupSettings()
+	groupKey := notificationGroupKey(evalContext, settings.By)
+	key := fmt.Sprintf("%d/%s", notifier.GetNotifierId(), groupKey)
+
+	g.Lock()
+	defer g.Unlock()
+
+	if group, exists := g.groups[key]; exists {
+		group.members = append(group.members, evalContext)
+		return
+	}
+
+	now := time.Now()
+	delay := settings.Wait
+	if next, exists := g.next[key]; exists && next.After(now.Add(delay)) {
+		delay = next.Sub(now)
+	}
+
+	g.groups[key] = &notificationGroup{
+		notifier: notifier,
+		key:      groupKey,
+		members:  []*EvalContext{evalContext},
+	}
+	g.log.Debug("Grouping notifications", "notifierId", notifier.GetNotifierId(), "group", groupKey, "wait", delay)
+	time.AfterFunc(delay, func() {
+		g.flush(key, settings.Interval)
+	})
+}
+
+func (g *notificationGrouper) flush(key string, interval time.Duration) {
+	g.Lock()
+	group := g.groups[key]
+	delete(g.groups, key)
+
+	now := time.Now()
+	for other, next := range g.next {
+		if next.Before(now) {
+			delete(g.next, other)
+		}
+	}
+	g.next[key] = now.Add(interval)
+	g.Unlock()
+
+	if group != nil {
+		g.send(group)
+	}
+}
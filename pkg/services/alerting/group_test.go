@@ -0,0 +1,106 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type groupingNotifier struct {
+	FakeNotifier
+	settings NotificationGroupSettings
+}
+
+func (n *groupingNotifier) GetGroupSettings() NotificationGroupSettings {
+	return n.settings
+}
+
+func TestNotificationGroups(t *testing.T) {
+	Convey("Test notification groups", t, func() {
+		Convey("Reads group settings", func() {
+			json, _ := simplejson.NewJson([]byte(`{"groupWait": "30s", "groupBy": "severity, labels.team"}`))
+			settings, err := NewNotificationGroupSettings(json)
+			So(err, ShouldBeNil)
+			So(settings.Wait, ShouldEqual, 30*time.Second)
+			So(settings.Interval, ShouldEqual, 30*time.Second)
+			So(settings.By, ShouldResemble, []string{"severity", "labels.team"})
+
+			settings, err = NewNotificationGroupSettings(simplejson.New())
+			So(err, ShouldBeNil)
+			So(settings.Wait, ShouldEqual, 0)
+			So(settings.By, ShouldResemble, []string{"dashboard"})
+
+			json, _ = simplejson.NewJson([]byte(`{"groupWait": "soon"}`))
+			_, err = NewNotificationGroupSettings(json)
+			So(err, ShouldEqual, ErrNotificationGroupSettingsInvalid)
+		})
+
+		Convey("Group key has the values of the group by keys", func() {
+			ctx := NewEvalContext(context.TODO(), &Rule{
+				Id:          1,
+				DashboardId: 2,
+				Severity:    "critical",
+				Labels:      map[string]string{"team": "database"},
+				State:       m.AlertStateAlerting,
+			})
+			key := notificationGroupKey(ctx, []string{"dashboard", "severity", "labels.team", "labels.env"})
+			So(key, ShouldEqual, "dashboard=2,severity=critical,labels.team=database,labels.env=")
+		})
+
+		Convey("Combines the alerts of a group", func() {
+			first := NewEvalContext(context.TODO(), &Rule{Id: 1, OrgId: 1, Name: "cpu", Message: "cpu is high", State: m.AlertStateAlerting})
+			first.Firing = true
+			first.EvalMatches = []*EvalMatch{{Metric: "cpu"}}
+			second := NewEvalContext(context.TODO(), &Rule{Id: 2, OrgId: 1, Name: "disk", State: m.AlertStateOK})
+			second.Error = errors.New("timeout")
+
+			ctx := newGroupEvalContext("dashboard=0", []*EvalContext{first, second})
+			So(ctx.Rule.State, ShouldEqual, m.AlertStateAlerting)
+			So(ctx.Firing, ShouldBeTrue)
+			So(len(ctx.EvalMatches), ShouldEqual, 1)
+			So(ctx.Error.Error(), ShouldEqual, "disk: timeout")
+			So(ctx.GetNotificationTitle(), ShouldEqual, "[Alerting] 2 alerts")
+			So(ctx.GetMessage(), ShouldEqual, "[Alerting] cpu: cpu is high\n[OK] disk")
+			So(ctx.RenderTemplate("{{range .Alerts}}{{.RuleName}} {{end}}"), ShouldEqual, "cpu disk ")
+		})
+
+		Convey("A group of one alert is sent as that alert", func() {
+			single := NewEvalContext(context.TODO(), &Rule{Id: 1, Name: "cpu", State: m.AlertStateAlerting})
+			ctx := newGroupEvalContext("dashboard=0", []*EvalContext{single})
+			So(ctx.Group, ShouldBeNil)
+			So(ctx.GetNotificationTitle(), ShouldEqual, "[Alerting] cpu")
+			So(ctx.GetAlertKey(), ShouldEqual, "alertId-1")
+		})
+
+		Convey("Sends the alerts that arrive during the group wait together", func() {
+			sent := make(chan *notificationGroup, 2)
+			grouper := newNotificationGrouper(func(group *notificationGroup) {
+				sent <- group
+			})
+			notifier := &groupingNotifier{settings: NotificationGroupSettings{
+				Wait:     10 * time.Millisecond,
+				Interval: 10 * time.Millisecond,
+				By:       []string{"severity"},
+			}}
+
+			grouper.add(notifier, NewEvalContext(context.TODO(), &Rule{Id: 1, Severity: "critical"}))
+			grouper.add(notifier, NewEvalContext(context.TODO(), &Rule{Id: 2, Severity: "critical"}))
+			grouper.add(notifier, NewEvalContext(context.TODO(), &Rule{Id: 3, Severity: "warning"}))
+
+			groups := map[string]int{}
+			for i := 0; i < 2; i++ {
+				select {
+				case group := <-sent:
+					groups[group.key] = len(group.members)
+				case <-time.After(time.Second):
+				}
+			}
+			So(groups, ShouldResemble, map[string]int{"severity=critical": 2, "severity=warning": 1})
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package alerting
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// InstanceName is the metric name followed by the sorted tags of a series,
+// like cpu{host=server1}.
+func InstanceName(metric string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return metric
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	return metric + "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// instanceKey identifies a series across evaluations of an alert.
+func instanceKey(metric string, tags map[string]string) string {
+	hash := sha1.Sum([]byte(InstanceName(metric, tags)))
+	return hex.EncodeToString(hash[:])
+}
+
+// diffInstances compares the series that matched in this evaluation with the
+// stored instances of the alert. It records the series that started or
+// stopped alerting in the eval context and returns the instances to save.
+// Instances keep their state when the evaluation failed or found no data, and
+// start alerting once the rule is alerting rather than pending.
+func diffInstances(evalContext *EvalContext, existing []*m.AlertInstance) []*m.AlertInstance {
+	if evalContext.Error != nil || (evalContext.NoDataFound && !evalContext.Firing) {
+		return nil
+	}
+
+	now := time.Now()
+	stored := make(map[string]*m.AlertInstance)
+	for _, instance := range existing {
+		stored[instance.InstanceKey] = instance
+	}
+
+	changed := make([]*m.AlertInstance, 0)
+	firing := make(map[string]bool)
+	if evalContext.Firing && evalContext.Rule.State == m.AlertStateAlerting {
+		for _, match := range evalContext.EvalMatches {
+			key := instanceKey(match.Metric, match.Tags)
+			if firing[key] {
+				continue
+			}
+			firing[key] = true
+
+			if instance, exists := stored[key]; exists && instance.State == m.AlertStateAlerting {
+				continue
+			}
+			changed = append(changed, &m.AlertInstance{
+				InstanceKey:  key,
+				Metric:       match.Metric,
+				Tags:         match.Tags,
+				State:        m.AlertStateAlerting,
+				NewStateDate: now,
+			})
+			evalContext.StartedInstances = append(evalContext.StartedInstances, match)
+		}
+	}
+
+	for _, instance := range existing {
+		if instance.State != m.AlertStateAlerting || firing[instance.InstanceKey] {
+			continue
+		}
+		changed = append(changed, &m.AlertInstance{
+			InstanceKey:  instance.InstanceKey,
+			Metric:       instance.Metric,
+			Tags:         instance.Tags,
+			State:        m.AlertStateOK,
+			NewStateDate: now,
+		})
+		evalContext.ResolvedInstances = append(evalContext.ResolvedInstances, &EvalMatch{
+			Metric: instance.Metric,
+			Tags:   instance.Tags,
+		})
+	}
+
+	return changed
+}
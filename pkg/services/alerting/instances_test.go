@@ -0,0 +1,69 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInstances(t *testing.T) {
+	Convey("Test alert instances", t, func() {
+		server1 := &EvalMatch{Metric: "cpu", Tags: map[string]string{"host": "server1"}, Value: null.FloatFrom(90)}
+		server2 := &EvalMatch{Metric: "cpu", Tags: map[string]string{"host": "server2"}, Value: null.FloatFrom(95)}
+		existing := []*m.AlertInstance{
+			{InstanceKey: instanceKey("cpu", map[string]string{"host": "server1"}), Metric: "cpu", Tags: server1.Tags, State: m.AlertStateAlerting},
+			{InstanceKey: instanceKey("cpu", map[string]string{"host": "server3"}), Metric: "cpu", Tags: map[string]string{"host": "server3"}, State: m.AlertStateAlerting},
+		}
+
+		Convey("Instance name sorts the tags", func() {
+			So(InstanceName("cpu", map[string]string{"host": "server1", "dc": "eu"}), ShouldEqual, "cpu{dc=eu, host=server1}")
+			So(InstanceName("cpu", nil), ShouldEqual, "cpu")
+			So(instanceKey("cpu", map[string]string{"a": "1", "b": "2"}), ShouldEqual, instanceKey("cpu", map[string]string{"b": "2", "a": "1"}))
+		})
+
+		Convey("Series that start and stop alerting are transitions", func() {
+			evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1, State: m.AlertStateAlerting})
+			evalContext.Firing = true
+			evalContext.EvalMatches = []*EvalMatch{server1, server2}
+
+			changed := diffInstances(evalContext, existing)
+			So(len(changed), ShouldEqual, 2)
+			So(changed[0].Tags["host"], ShouldEqual, "server2")
+			So(changed[0].State, ShouldEqual, m.AlertStateAlerting)
+			So(changed[1].Tags["host"], ShouldEqual, "server3")
+			So(changed[1].State, ShouldEqual, m.AlertStateOK)
+
+			So(len(evalContext.StartedInstances), ShouldEqual, 1)
+			So(evalContext.StartedInstances[0], ShouldEqual, server2)
+			So(len(evalContext.ResolvedInstances), ShouldEqual, 1)
+			So(evalContext.GetInstancesText(), ShouldEqual, "Started: cpu{host=server2}\nResolved: cpu{host=server3}")
+		})
+
+		Convey("All instances resolve when the rule stops firing", func() {
+			evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1})
+			changed := diffInstances(evalContext, existing)
+			So(len(changed), ShouldEqual, 2)
+			So(len(evalContext.ResolvedInstances), ShouldEqual, 2)
+		})
+
+		Convey("Series do not start alerting while the rule is pending", func() {
+			evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1, State: m.AlertStatePending})
+			evalContext.Firing = true
+			evalContext.EvalMatches = []*EvalMatch{server2}
+
+			changed := diffInstances(evalContext, []*m.AlertInstance{})
+			So(len(changed), ShouldEqual, 0)
+			So(len(evalContext.StartedInstances), ShouldEqual, 0)
+		})
+
+		Convey("Instances keep their state on errors and no data", func() {
+			evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1})
+			evalContext.NoDataFound = true
+			So(len(diffInstances(evalContext, existing)), ShouldEqual, 0)
+			So(len(evalContext.ResolvedInstances), ShouldEqual, 0)
+		})
+	})
+}
@@ -16,9 +16,11 @@ type Notifier interface {
 	GetType() string
 	NeedsImage() bool
 	PassesFilter(rule *Rule) bool
+	ShouldNotify(evalContext *EvalContext) bool
 
 	GetNotifierId() int64
 	GetIsDefault() bool
+	GetGroupSettings() NotificationGroupSettings
 }
 
 type NotifierSlice []Notifier
@@ -3,11 +3,14 @@ package alerting
 import "github.com/grafana/grafana/pkg/components/null"
 
 type Job struct {
-	Offset     int64
-	OffsetWait bool
-	Delay      bool
-	Running    bool
-	Rule       *Rule
+	Offset      int64
+	OffsetWait  bool
+	Delay       bool
+	Running     bool
+	Rule        *Rule
+	ScheduledAt int64  // interval the job was due for, claimed in the alert execution ledger
+	NodeId      string // cluster node the job was scheduled on
+	Partition   string // partition of the cluster the job was scheduled for
 }
 
 type ResultLogEntry struct {
@@ -3,6 +3,7 @@ package alerting
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -30,17 +31,33 @@ func NewNotificationService() NotificationService {
 }
 
 type notificationService struct {
-	log log.Logger
+	log     log.Logger
+	grouper *notificationGrouper
 }
 
 func newNotificationService() *notificationService {
-	return &notificationService{
+	n := &notificationService{
 		log: log.New("alerting.notifier"),
 	}
+	n.grouper = newNotificationGrouper(n.sendGroup)
+	return n
 }
 
 func (n *notificationService) Send(context *EvalContext) error {
-	notifiers, err := n.getNotifiers(context.Rule.OrgId, context.Rule.Notifications, context)
+	silences, err := context.GetSilences()
+	if err != nil {
+		n.log.Error("Failed to get alert silences", "ruleId", context.Rule.Id, "error", err)
+	} else if len(silences) > 0 {
+		n.log.Info("Notifications are silenced", "ruleId", context.Rule.Id, "silenceId", silences[0].Id)
+		return nil
+	}
+
+	notificationIds, err := getNotificationIds(context.Rule)
+	if err != nil {
+		return err
+	}
+
+	notifiers, err := n.getNotifiers(context.Rule.OrgId, notificationIds, context)
 	if err != nil {
 		return err
 	}
@@ -51,13 +68,32 @@ func (n *notificationService) Send(context *EvalContext) error {
 		return nil
 	}
 
-	if notifiers.ShouldUploadImage() {
+	// standalone rules have no panel to render
+	if notifiers.ShouldUploadImage() && context.Rule.PanelId != 0 {
 		if err = n.uploadImage(context); err != nil {
 			n.log.Error("Failed to upload alert panel image.", "error", err)
 		}
 	}
 
-	return n.sendNotifications(context, notifiers)
+	// notifiers that group notifications send them when the group wait is over
+	immediate := make([]Notifier, 0, len(notifiers))
+	for _, notifier := range notifiers {
+		if notifier.GetGroupSettings().Wait > 0 && !context.IsTestRun {
+			n.grouper.add(notifier, context)
+		} else {
+			immediate = append(immediate, notifier)
+		}
+	}
+
+	return n.sendNotifications(context, immediate)
+}
+
+func (n *notificationService) sendGroup(group *notificationGroup) {
+	context := newGroupEvalContext(group.key, group.members)
+	n.log.Info("Sending grouped notification", "group", group.key, "alerts", len(group.members))
+	if err := n.sendNotifications(context, []Notifier{group.notifier}); err != nil {
+		n.log.Error("Failed to send grouped notification", "group", group.key, "error", err)
+	}
 }
 
 func (n *notificationService) sendNotifications(context *EvalContext, notifiers []Notifier) error {
@@ -66,12 +102,42 @@ func (n *notificationService) sendNotifications(context *EvalContext, notifiers
 	for _, notifier := range notifiers {
 		not := notifier //avoid updating scope variable in go routine
 		n.log.Info("Sending notification", "type", not.GetType(), "id", not.GetNotifierId(), "isDefault", not.GetIsDefault())
-		g.Go(func() error { return not.Notify(context) })
+		g.Go(func() error {
+			err := not.Notify(context)
+			n.recordNotification(context, not, err == nil)
+			return err
+		})
 	}
 
 	return g.Wait()
 }
 
+// recordNotification keeps the time of the notification in the journal that
+// reminders are sent from.
+func (n *notificationService) recordNotification(context *EvalContext, notifier Notifier, success bool) {
+	if context.IsTestRun {
+		return
+	}
+
+	if context.Group != nil {
+		for _, member := range context.Group {
+			n.recordNotification(member, notifier, success)
+		}
+		return
+	}
+
+	cmd := &m.RecordNotificationJournalCommand{
+		OrgId:      context.Rule.OrgId,
+		AlertId:    context.Rule.Id,
+		NotifierId: notifier.GetNotifierId(),
+		SentAt:     time.Now().Unix(),
+		Success:    success,
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		n.log.Error("Failed to record notification", "alertId", context.Rule.Id, "notifierId", notifier.GetNotifierId(), "error", err)
+	}
+}
+
 func (n *notificationService) uploadImage(context *EvalContext) (err error) {
 	uploader, err := imguploader.NewImageUploader()
 	if err != nil {
@@ -119,7 +185,7 @@ func (n *notificationService) getNotifiers(orgId int64, notificationIds []int64,
 			return nil, err
 		} else {
 			if shouldUseNotification(not, context) {
-				result = append(result, not)
+				result = append(result, newRetryNotifier(not))
 			}
 		}
 	}
@@ -127,6 +193,33 @@ func (n *notificationService) getNotifiers(orgId int64, notificationIds []int64,
 	return result, nil
 }
 
+// getNotificationIds returns the notifications of the rule together with the
+// ones the routing policies of the org send it to.
+func getNotificationIds(rule *Rule) ([]int64, error) {
+	query := &m.GetRoutedNotificationIdsQuery{
+		OrgId:       rule.OrgId,
+		DashboardId: rule.DashboardId,
+		RuleName:    rule.Name,
+		Severity:    rule.Severity,
+		Labels:      rule.Labels,
+	}
+	if err := bus.Dispatch(query); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(rule.Notifications)+len(query.Result))
+	seen := make(map[int64]bool)
+	for _, list := range [][]int64{rule.Notifications, query.Result} {
+		for _, id := range list {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
 func (n *notificationService) createNotifierFor(model *m.AlertNotification) (Notifier, error) {
 	notifierPlugin, found := notifierFactories[model.Type]
 	if !found {
@@ -137,6 +230,10 @@ func (n *notificationService) createNotifierFor(model *m.AlertNotification) (Not
 }
 
 func shouldUseNotification(notifier Notifier, context *EvalContext) bool {
+	if !notifier.ShouldNotify(context) {
+		return false
+	}
+
 	if !context.Firing {
 		return true
 	}
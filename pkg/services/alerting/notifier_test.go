@@ -1,17 +1,24 @@
 package alerting
 
 import (
+	"context"
 	"testing"
 
 	"fmt"
 
+	"github.com/go-xorm/xorm"
+
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/models"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/sqlutil"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
 type FakeNotifier struct {
 	FakeMatchResult bool
+	SkipNotify      bool
 }
 
 func (fn *FakeNotifier) GetType() string {
@@ -30,12 +37,20 @@ func (n *FakeNotifier) GetIsDefault() bool {
 	return false
 }
 
+func (n *FakeNotifier) GetGroupSettings() NotificationGroupSettings {
+	return NotificationGroupSettings{}
+}
+
 func (fn *FakeNotifier) Notify(alertResult *EvalContext) error { return nil }
 
 func (fn *FakeNotifier) PassesFilter(rule *Rule) bool {
 	return fn.FakeMatchResult
 }
 
+func (fn *FakeNotifier) ShouldNotify(evalContext *EvalContext) bool {
+	return !fn.SkipNotify
+}
+
 func TestAlertNotificationExtraction(t *testing.T) {
 
 	Convey("Notifier tests", t, func() {
@@ -51,6 +66,18 @@ func TestAlertNotificationExtraction(t *testing.T) {
 			So(shouldUseNotification(notifier, ctx), ShouldBeTrue)
 		})
 
+		Convey("notifier that has nothing to send", func() {
+			ctx := &EvalContext{
+				Firing: true,
+				Rule: &Rule{
+					State: m.AlertStateAlerting,
+				},
+			}
+			notifier := &FakeNotifier{FakeMatchResult: true, SkipNotify: true}
+
+			So(shouldUseNotification(notifier, ctx), ShouldBeFalse)
+		})
+
 		Convey("execution error cannot be ignored", func() {
 			ctx := &EvalContext{
 				Firing: true,
@@ -87,3 +114,43 @@ func TestAlertNotificationExtraction(t *testing.T) {
 		})
 	})
 }
+
+func TestAlertNotificationRouting(t *testing.T) {
+	Convey("Routed notifications are added to the notifications of the rule", t, func() {
+		var routed *m.GetRoutedNotificationIdsQuery
+		bus.AddHandler("test", func(query *m.GetRoutedNotificationIdsQuery) error {
+			routed = query
+			query.Result = []int64{2, 3}
+			return nil
+		})
+
+		rule := &Rule{OrgId: 1, DashboardId: 4, Severity: "critical", Notifications: []int64{1, 2}}
+		ids, err := getNotificationIds(rule)
+		So(err, ShouldBeNil)
+		So(ids, ShouldResemble, []int64{1, 2, 3})
+		So(routed.Severity, ShouldEqual, "critical")
+		So(routed.DashboardId, ShouldEqual, 4)
+		So(len(rule.Notifications), ShouldEqual, 2)
+	})
+}
+
+func TestNotificationJournal(t *testing.T) {
+	Convey("Sent notifications are recorded in the journal through the sql store", t, func() {
+		x, err := xorm.NewEngine(sqlutil.TestDB_Sqlite3.DriverName, sqlutil.TestDB_Sqlite3.ConnStr)
+		So(err, ShouldBeNil)
+		sqlutil.CleanDB(x)
+		So(sqlstore.SetEngine(x), ShouldBeNil)
+
+		// other tests replace these handlers, so register the ones of the sql store again
+		bus.AddHandler("sql", sqlstore.RecordNotificationJournal)
+		bus.AddHandler("sql", sqlstore.GetLatestNotification)
+
+		evalContext := NewEvalContext(context.Background(), &Rule{Id: 1, OrgId: 1, State: m.AlertStateAlerting})
+		So(newNotificationService().sendNotifications(evalContext, []Notifier{&FakeNotifier{}}), ShouldBeNil)
+
+		query := &m.GetLatestNotificationQuery{OrgId: 1, AlertId: 1, NotifierId: 0}
+		So(bus.Dispatch(query), ShouldBeNil)
+		So(query.Result, ShouldNotBeNil)
+		So(query.Result.Success, ShouldBeTrue)
+	})
+}
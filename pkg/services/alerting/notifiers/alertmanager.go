@@ -0,0 +1,215 @@
+package notifiers
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "alertmanager",
+		Name:        "Prometheus Alertmanager",
+		Description: "Sends alerts to Prometheus Alertmanager",
+		Factory:     NewAlertmanagerNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Alertmanager settings</h3>
+      <div class="gf-form">
+        <span class="gf-form-label width-10">Url</span>
+        <input type="text" required class="gf-form-input max-width-26" ng-model="ctrl.model.settings.url" placeholder="http://localhost:9093"></input>
+      </div>
+    `,
+	})
+}
+
+// Alertmanager resolves firing alerts at their endsAt, so firing alerts are
+// posted again at least this often and end after a few missed posts.
+const (
+	alertmanagerResendInterval = time.Minute
+	alertmanagerMissedPosts    = 4
+)
+
+var alertmanagerInvalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+func NewAlertmanagerNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	base := NewNotifierBase(model)
+	if !base.SendReminder || base.Frequency <= 0 || base.Frequency > alertmanagerResendInterval {
+		base.SendReminder = true
+		base.Frequency = alertmanagerResendInterval
+	}
+
+	return &AlertmanagerNotifier{
+		NotifierBase: base,
+		Url:          strings.TrimSuffix(url, "/"),
+		log:          log.New("alerting.notifier.alertmanager"),
+	}, nil
+}
+
+type AlertmanagerNotifier struct {
+	NotifierBase
+	Url string
+	log log.Logger
+}
+
+func (this *AlertmanagerNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Sending alertmanager alerts", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_Alertmanager.Inc(1)
+
+	// a group of notifications is posted as the alerts of its rules
+	contexts := evalContext.Group
+	if contexts == nil {
+		contexts = []*alerting.EvalContext{evalContext}
+	}
+
+	alerts := make([]map[string]interface{}, 0)
+	for _, context := range contexts {
+		alerts = append(alerts, this.createAlerts(context)...)
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	data, _ := json.Marshal(alerts)
+	cmd := &m.SendWebhookSync{Url: this.Url + "/api/v1/alerts", Body: string(data)}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send alertmanager alerts", "error", err, "alertmanager", this.Name)
+		return err
+	}
+
+	return nil
+}
+
+// createAlerts returns an alert for each series that is firing, and resolves
+// the series that stopped firing. Rules in other states than alerting and ok
+// have nothing to post.
+func (this *AlertmanagerNotifier) createAlerts(evalContext *alerting.EvalContext) []map[string]interface{} {
+	alerts := make([]map[string]interface{}, 0)
+	now := time.Now()
+
+	switch evalContext.Rule.State {
+	case m.AlertStateAlerting:
+		startsAt := evalContext.Rule.NewStateDate
+		if startsAt.IsZero() {
+			startsAt = evalContext.StartTime
+		}
+
+		endsAt := now.Add(alertmanagerMissedPosts * alertmanagerPostInterval(evalContext.Rule))
+
+		for _, match := range evalContext.EvalMatches {
+			alert := this.createAlert(evalContext, match)
+			alert["startsAt"] = startsAt
+			alert["endsAt"] = endsAt
+			alerts = append(alerts, alert)
+		}
+		if len(evalContext.EvalMatches) == 0 {
+			alert := this.createAlert(evalContext, nil)
+			alert["startsAt"] = startsAt
+			alert["endsAt"] = endsAt
+			alerts = append(alerts, alert)
+		}
+
+		for _, match := range evalContext.ResolvedInstances {
+			alert := this.createAlert(evalContext, match)
+			alert["endsAt"] = now
+			alerts = append(alerts, alert)
+		}
+
+	case m.AlertStateOK:
+		for _, match := range evalContext.ResolvedInstances {
+			alert := this.createAlert(evalContext, match)
+			alert["endsAt"] = now
+			alerts = append(alerts, alert)
+		}
+		if len(evalContext.ResolvedInstances) == 0 {
+			alert := this.createAlert(evalContext, nil)
+			alert["endsAt"] = now
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts
+}
+
+func (this *AlertmanagerNotifier) createAlert(evalContext *alerting.EvalContext, match *alerting.EvalMatch) map[string]interface{} {
+	rule := evalContext.Rule
+
+	labels := map[string]string{}
+	for key, value := range rule.Labels {
+		labels[alertmanagerLabelName(key)] = value
+	}
+	if rule.Severity != "" {
+		labels["severity"] = rule.Severity
+	}
+	if rule.DashboardId != 0 {
+		if slug, err := evalContext.GetDashboardSlug(); err == nil {
+			labels["dashboard"] = slug
+		}
+		labels["panel"] = strconv.FormatInt(rule.PanelId, 10)
+	}
+
+	annotations := map[string]string{}
+	if message := this.GetMessage(evalContext); message != "" {
+		annotations["description"] = message
+	}
+	if evalContext.ImagePublicUrl != "" {
+		annotations["image"] = evalContext.ImagePublicUrl
+	}
+	if evalContext.Error != nil {
+		annotations["error"] = evalContext.Error.Error()
+	}
+
+	if match != nil {
+		labels["metric"] = match.Metric
+		for key, value := range match.Tags {
+			labels[alertmanagerLabelName(key)] = value
+		}
+		annotations["value"] = match.Value.String()
+	}
+
+	// the name of the rule is set last, so no tag can override it
+	labels["alertname"] = evalContext.GetRuleName()
+
+	alert := map[string]interface{}{
+		"labels":      labels,
+		"annotations": annotations,
+	}
+	if ruleUrl, err := evalContext.GetRuleUrl(); err == nil {
+		alert["generatorURL"] = ruleUrl
+	}
+
+	return alert
+}
+
+// alertmanagerPostInterval is how often the alerts of a rule are posted, alerts
+// are only posted when the rule is evaluated.
+func alertmanagerPostInterval(rule *alerting.Rule) time.Duration {
+	if frequency := time.Duration(rule.Frequency) * time.Second; frequency > alertmanagerResendInterval {
+		return frequency
+	}
+	return alertmanagerResendInterval
+}
+
+// alertmanagerLabelName replaces the characters that Prometheus does not
+// allow in label names, like the dots of graphite tags.
+func alertmanagerLabelName(name string) string {
+	name = alertmanagerInvalidLabelChars.ReplaceAllString(name, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
@@ -0,0 +1,126 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertmanagerNotifier(t *testing.T) {
+	Convey("Alertmanager notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "alertmanager",
+					Type:     "alertmanager",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewAlertmanagerNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+          "url": "http://localhost:9093/"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "alertmanager",
+					Type:     "alertmanager",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewAlertmanagerNotifier(model)
+				alertmanagerNotifier := not.(*AlertmanagerNotifier)
+
+				So(err, ShouldBeNil)
+				So(alertmanagerNotifier.Name, ShouldEqual, "alertmanager")
+				So(alertmanagerNotifier.Type, ShouldEqual, "alertmanager")
+				So(alertmanagerNotifier.Url, ShouldEqual, "http://localhost:9093")
+				So(alertmanagerNotifier.SendReminder, ShouldBeTrue)
+				So(alertmanagerNotifier.Frequency, ShouldEqual, time.Minute)
+			})
+		})
+
+		Convey("Posting alerts", func() {
+			receiver := newWebhookReceiver()
+			defer receiver.Close()
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", receiver.URL)
+			not, _ := NewAlertmanagerNotifier(&m.AlertNotification{Name: "alertmanager", Type: "alertmanager", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Id:       1,
+				Name:     "cpu",
+				Message:  "cpu is high",
+				Severity: "critical",
+				State:    m.AlertStateAlerting,
+			})
+
+			Convey("firing series are posted with their tags", func() {
+				evalContext.EvalMatches = []*alerting.EvalMatch{
+					{Metric: "cpu", Value: null.FloatFrom(95), Tags: map[string]string{"host": "server1", "host.dc": "eu"}},
+				}
+				evalContext.ResolvedInstances = []*alerting.EvalMatch{
+					{Metric: "cpu", Tags: map[string]string{"host": "server2"}},
+				}
+
+				So(not.Notify(evalContext), ShouldBeNil)
+				So(receiver.path, ShouldEqual, "/api/v1/alerts")
+
+				alerts := receiver.Json()
+				firing := alerts.GetIndex(0)
+				So(firing.GetPath("labels", "alertname").MustString(), ShouldEqual, "cpu")
+				So(firing.GetPath("labels", "severity").MustString(), ShouldEqual, "critical")
+				So(firing.GetPath("labels", "host").MustString(), ShouldEqual, "server1")
+				So(firing.GetPath("labels", "host_dc").MustString(), ShouldEqual, "eu")
+				So(firing.GetPath("annotations", "description").MustString(), ShouldEqual, "cpu is high")
+				So(firing.GetPath("annotations", "value").MustString(), ShouldEqual, "95.000")
+				So(firing.Get("startsAt").MustString(), ShouldNotEqual, "")
+				So(firing.Get("endsAt").MustString(), ShouldNotEqual, "")
+				So(firing.Get("generatorURL").MustString(), ShouldNotEqual, "")
+
+				resolved := alerts.GetIndex(1)
+				So(resolved.GetPath("labels", "host").MustString(), ShouldEqual, "server2")
+				So(resolved.Get("endsAt").MustString(), ShouldNotEqual, "")
+			})
+
+			Convey("firing alerts of a slow rule end after four evaluations", func() {
+				evalContext.Rule.Frequency = 600
+
+				So(not.Notify(evalContext), ShouldBeNil)
+
+				endsAt, err := time.Parse(time.RFC3339, receiver.Json().GetIndex(0).Get("endsAt").MustString())
+				So(err, ShouldBeNil)
+				So(endsAt.After(time.Now().Add(39*time.Minute)), ShouldBeTrue)
+				So(endsAt.Before(time.Now().Add(41*time.Minute)), ShouldBeTrue)
+			})
+
+			Convey("ok resolves the alert", func() {
+				evalContext.Rule.State = m.AlertStateOK
+
+				So(not.Notify(evalContext), ShouldBeNil)
+
+				alert := receiver.Json().GetIndex(0)
+				So(alert.GetPath("labels", "alertname").MustString(), ShouldEqual, "cpu")
+				So(alert.Get("endsAt").MustString(), ShouldNotEqual, "")
+				_, hasStartsAt := alert.CheckGet("startsAt")
+				So(hasStartsAt, ShouldBeFalse)
+			})
+		})
+	})
+}
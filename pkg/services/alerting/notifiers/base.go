@@ -1,27 +1,44 @@
 package notifiers
 
 import (
-	"github.com/grafana/grafana/pkg/components/simplejson"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/alerting"
 )
 
+var notifierBaseLog = log.New("alerting.notifier")
+
 type NotifierBase struct {
-	Name        string
-	Type        string
-	Id          int64
-	IsDeault    bool
-	UploadImage bool
+	Name          string
+	Type          string
+	Id            int64
+	IsDeault      bool
+	UploadImage   bool
+	SendReminder  bool
+	Frequency     time.Duration
+	Template      string
+	GroupSettings alerting.NotificationGroupSettings
 }
 
-func NewNotifierBase(id int64, isDefault bool, name, notifierType string, model *simplejson.Json) NotifierBase {
-	uploadImage := model.Get("uploadImage").MustBool(true)
+func NewNotifierBase(model *m.AlertNotification) NotifierBase {
+	uploadImage := model.Settings.Get("uploadImage").MustBool(true)
+
+	// invalid group settings are rejected when the notification is saved
+	groupSettings, _ := alerting.NewNotificationGroupSettings(model.Settings)
 
 	return NotifierBase{
-		Id:          id,
-		Name:        name,
-		IsDeault:    isDefault,
-		Type:        notifierType,
-		UploadImage: uploadImage,
+		Id:            model.Id,
+		Name:          model.Name,
+		IsDeault:      model.IsDefault,
+		Type:          model.Type,
+		UploadImage:   uploadImage,
+		SendReminder:  model.SendReminder,
+		Frequency:     model.Frequency,
+		Template:      model.Settings.Get("template").MustString(),
+		GroupSettings: groupSettings,
 	}
 }
 
@@ -29,6 +46,41 @@ func (n *NotifierBase) PassesFilter(rule *alerting.Rule) bool {
 	return true
 }
 
+// ShouldNotify sends state changes and series that started or resolved. While
+// the rule stays alerting it sends a reminder when the last notification is
+// older than the frequency of the notification.
+func (n *NotifierBase) ShouldNotify(context *alerting.EvalContext) bool {
+	if context.ShouldUpdateAlertState() || len(context.StartedInstances) > 0 || len(context.ResolvedInstances) > 0 {
+		return true
+	}
+
+	if !n.SendReminder || n.Frequency <= 0 || context.Rule.State != m.AlertStateAlerting {
+		return false
+	}
+
+	// only a notification that was delivered resets the reminder clock
+	query := &m.GetLatestNotificationQuery{OrgId: context.Rule.OrgId, AlertId: context.Rule.Id, NotifierId: n.Id, SuccessOnly: true}
+	if err := bus.Dispatch(query); err != nil {
+		notifierBaseLog.Error("Failed to get latest notification", "alertId", context.Rule.Id, "notifierId", n.Id, "error", err)
+		return false
+	}
+
+	if query.Result == nil || !query.Result.Success {
+		return true
+	}
+
+	return time.Now().Unix()-query.Result.SentAt >= int64(n.Frequency/time.Second)
+}
+
+// GetMessage returns the template of the notification rendered, or the rule
+// message if the notification has no template.
+func (n *NotifierBase) GetMessage(context *alerting.EvalContext) string {
+	if n.Template != "" {
+		return context.RenderTemplate(n.Template)
+	}
+	return context.GetMessage()
+}
+
 func (n *NotifierBase) GetType() string {
 	return n.Type
 }
@@ -44,3 +96,27 @@ func (n *NotifierBase) GetNotifierId() int64 {
 func (n *NotifierBase) GetIsDefault() bool {
 	return n.IsDeault
 }
+
+func (n *NotifierBase) GetGroupSettings() alerting.NotificationGroupSettings {
+	return n.GroupSettings
+}
+
+// notifyEach sends the alerts of a group of notifications one by one, to the
+// services that keep an incident per alert rule. Sending a group as one alert
+// would resolve the incident of the group while some of its rules still fire.
+// Every alert is sent, the first error is returned.
+func notifyEach(evalContext *alerting.EvalContext, notify func(evalContext *alerting.EvalContext) error) error {
+	if evalContext.Group == nil {
+		return notify(evalContext)
+	}
+
+	var firstErr error
+	for _, member := range evalContext.Group {
+		memberContext := *member
+		memberContext.Ctx = evalContext.Ctx
+		if err := notify(&memberContext); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,75 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBaseNotifier(t *testing.T) {
+	Convey("Base notifier tests", t, func() {
+		var latest *m.AlertNotificationJournal
+		bus.AddHandler("test", func(query *m.GetLatestNotificationQuery) error {
+			query.Result = nil
+			if latest != nil && (latest.Success || !query.SuccessOnly) {
+				query.Result = latest
+			}
+			return nil
+		})
+
+		notifier := NewNotifierBase(&m.AlertNotification{
+			Id:           1,
+			Settings:     simplejson.New(),
+			SendReminder: true,
+			Frequency:    15 * time.Minute,
+		})
+		evalContext := alerting.NewEvalContext(context.TODO(), &alerting.Rule{Id: 1, State: m.AlertStateAlerting})
+		evalContext.PrevAlertState = m.AlertStateAlerting
+
+		Convey("state changes are sent", func() {
+			evalContext.PrevAlertState = m.AlertStateOK
+			latest = &m.AlertNotificationJournal{SentAt: time.Now().Unix(), Success: true}
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeTrue)
+		})
+
+		Convey("reminder is sent when the frequency has passed", func() {
+			latest = &m.AlertNotificationJournal{SentAt: time.Now().Add(-16 * time.Minute).Unix(), Success: true}
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeTrue)
+		})
+
+		Convey("reminder is not sent before the frequency has passed", func() {
+			latest = &m.AlertNotificationJournal{SentAt: time.Now().Add(-5 * time.Minute).Unix(), Success: true}
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeFalse)
+		})
+
+		Convey("failed send does not reset the reminder clock", func() {
+			latest = &m.AlertNotificationJournal{SentAt: time.Now().Add(-5 * time.Minute).Unix(), Success: false}
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeTrue)
+		})
+
+		Convey("reminder is not sent when the rule is ok", func() {
+			evalContext.PrevAlertState = m.AlertStateOK
+			evalContext.Rule.State = m.AlertStateOK
+			latest = nil
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeFalse)
+		})
+
+		Convey("reminders are not sent unless enabled", func() {
+			notifier.SendReminder = false
+			latest = nil
+
+			So(notifier.ShouldNotify(evalContext), ShouldBeFalse)
+		})
+	})
+}
@@ -33,7 +33,7 @@ func NewDingDingNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 	}
 
 	return &DingDingNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Url:          url,
 		log:          log.New("alerting.notifier.dingding"),
 	}, nil
@@ -56,7 +56,7 @@ func (this *DingDingNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 	this.log.Info("messageUrl:" + messageUrl)
 
-	message := evalContext.Rule.Message
+	message := this.GetMessage(evalContext)
 	picUrl := evalContext.ImagePublicUrl
 	title := evalContext.GetNotificationTitle()
 
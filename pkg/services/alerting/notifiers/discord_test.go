@@ -0,0 +1,89 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDiscordNotifier(t *testing.T) {
+	Convey("Discord notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "discord",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewDiscordNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+          "url": "http://google.com",
+          "content": "@here"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "discord",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewDiscordNotifier(model)
+				discordNotifier := not.(*DiscordNotifier)
+
+				So(err, ShouldBeNil)
+				So(discordNotifier.Name, ShouldEqual, "ops")
+				So(discordNotifier.Type, ShouldEqual, "discord")
+				So(discordNotifier.Url, ShouldEqual, "http://google.com")
+				So(discordNotifier.Content, ShouldEqual, "@here")
+			})
+		})
+
+		Convey("Sends an embed", func() {
+			receiver := newWebhookReceiver()
+			defer receiver.Close()
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", receiver.URL)
+			settingsJSON.Set("content", "@here")
+			not, _ := NewDiscordNotifier(&m.AlertNotification{Name: "ops", Type: "discord", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Id:      1,
+				Name:    "cpu",
+				Message: "cpu is high",
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "server1", Value: null.FloatFrom(95)}}
+			evalContext.ImagePublicUrl = "http://image.url"
+
+			So(not.Notify(evalContext), ShouldBeNil)
+
+			body := receiver.Json()
+			So(body.Get("content").MustString(), ShouldEqual, "@here")
+
+			embed := body.Get("embeds").GetIndex(0)
+			So(embed.Get("title").MustString(), ShouldEqual, "[Alerting] cpu")
+			So(embed.Get("description").MustString(), ShouldEqual, "cpu is high")
+			So(embed.Get("color").MustInt64(), ShouldEqual, 0xD63232)
+			So(embed.Get("fields").GetIndex(0).Get("name").MustString(), ShouldEqual, "server1")
+			So(embed.Get("fields").GetIndex(0).Get("value").MustString(), ShouldEqual, "95.000")
+			So(embed.Get("image").Get("url").MustString(), ShouldEqual, "http://image.url")
+		})
+	})
+}
@@ -53,7 +53,7 @@ func NewEmailNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	})
 
 	return &EmailNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Addresses:    addresses,
 		log:          log.New("alerting.notifier.email"),
 	}, nil
@@ -78,17 +78,19 @@ func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 		SendEmailCommand: m.SendEmailCommand{
 			Subject: evalContext.GetNotificationTitle(),
 			Data: map[string]interface{}{
-				"Title":        evalContext.GetNotificationTitle(),
-				"State":        evalContext.Rule.State,
-				"Name":         evalContext.Rule.Name,
-				"StateModel":   evalContext.GetStateModel(),
-				"Message":      evalContext.Rule.Message,
-				"Error":        error,
-				"RuleUrl":      ruleUrl,
-				"ImageLink":    "",
-				"EmbededImage": "",
-				"AlertPageUrl": setting.AppUrl + "alerting",
-				"EvalMatches":  evalContext.EvalMatches,
+				"Title":             evalContext.GetNotificationTitle(),
+				"State":             evalContext.Rule.State,
+				"Name":              evalContext.GetRuleName(),
+				"StateModel":        evalContext.GetStateModel(),
+				"Message":           this.GetMessage(evalContext),
+				"Error":             error,
+				"RuleUrl":           ruleUrl,
+				"ImageLink":         "",
+				"EmbededImage":      "",
+				"AlertPageUrl":      setting.AppUrl + "alerting",
+				"EvalMatches":       evalContext.EvalMatches,
+				"StartedInstances":  instanceNames(evalContext.StartedInstances),
+				"ResolvedInstances": instanceNames(evalContext.ResolvedInstances),
 			},
 			To:           this.Addresses,
 			Template:     "alert_notification.html",
@@ -115,3 +117,11 @@ func (this *EmailNotifier) Notify(evalContext *alerting.EvalContext) error {
 	return nil
 
 }
+
+func instanceNames(matches []*alerting.EvalMatch) []string {
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, alerting.InstanceName(match.Metric, match.Tags))
+	}
+	return names
+}
@@ -0,0 +1,133 @@
+package notifiers
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+func init() {
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{
+		Type:        "googlechat",
+		Name:        "Google Chat",
+		Description: "Sends notifications to Google Chat via webhooks",
+		Factory:     NewGoogleChatNotifier,
+		OptionsTemplate: `
+      <h3 class="page-heading">Google Chat settings</h3>
+      <div class="gf-form max-width-30">
+        <span class="gf-form-label width-6">Url</span>
+        <input type="text" required class="gf-form-input max-width-30" ng-model="ctrl.model.settings.url" placeholder="Google Chat incoming webhook url"></input>
+      </div>
+    `,
+	})
+}
+
+func NewGoogleChatNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
+	url := model.Settings.Get("url").MustString()
+	if url == "" {
+		return nil, alerting.ValidationError{Reason: "Could not find url property in settings"}
+	}
+
+	return &GoogleChatNotifier{
+		NotifierBase: NewNotifierBase(model),
+		Url:          url,
+		log:          log.New("alerting.notifier.googlechat"),
+	}, nil
+}
+
+type GoogleChatNotifier struct {
+	NotifierBase
+	Url string
+	log log.Logger
+}
+
+func (this *GoogleChatNotifier) Notify(evalContext *alerting.EvalContext) error {
+	this.log.Info("Executing google chat notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
+	metrics.M_Alerting_Notification_Sent_GoogleChat.Inc(1)
+
+	ruleUrl, err := evalContext.GetRuleUrl()
+	if err != nil {
+		this.log.Error("Failed get rule link", "error", err)
+		return err
+	}
+
+	widgets := make([]map[string]interface{}, 0)
+	if evalContext.Rule.State != m.AlertStateOK { //dont add message when going back to alert state ok.
+		if message := this.GetMessage(evalContext); message != "" {
+			widgets = append(widgets, map[string]interface{}{
+				"textParagraph": map[string]interface{}{"text": message},
+			})
+		}
+	}
+
+	for _, evt := range evalContext.EvalMatches {
+		widgets = append(widgets, map[string]interface{}{
+			"keyValue": map[string]interface{}{
+				"topLabel": evt.Metric,
+				"content":  evt.Value.String(),
+			},
+		})
+	}
+
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		widgets = append(widgets, map[string]interface{}{
+			"keyValue": map[string]interface{}{
+				"topLabel":         "Instances",
+				"content":          instances,
+				"contentMultiline": "true",
+			},
+		})
+	}
+
+	if evalContext.Error != nil {
+		widgets = append(widgets, map[string]interface{}{
+			"keyValue": map[string]interface{}{
+				"topLabel":         "Error message",
+				"content":          evalContext.Error.Error(),
+				"contentMultiline": "true",
+			},
+		})
+	}
+
+	if evalContext.ImagePublicUrl != "" {
+		widgets = append(widgets, map[string]interface{}{
+			"image": map[string]interface{}{"imageUrl": evalContext.ImagePublicUrl},
+		})
+	}
+
+	widgets = append(widgets, map[string]interface{}{
+		"buttons": []map[string]interface{}{
+			{
+				"textButton": map[string]interface{}{
+					"text":    "OPEN IN GRAFANA",
+					"onClick": map[string]interface{}{"openLink": map[string]interface{}{"url": ruleUrl}},
+				},
+			},
+		},
+	})
+
+	body := map[string]interface{}{
+		"previewText":  evalContext.GetNotificationTitle(),
+		"fallbackText": evalContext.GetNotificationTitle(),
+		"cards": []map[string]interface{}{
+			{
+				"header":   map[string]interface{}{"title": evalContext.GetNotificationTitle()},
+				"sections": []map[string]interface{}{{"widgets": widgets}},
+			},
+		},
+	}
+
+	data, _ := json.Marshal(&body)
+	cmd := &m.SendWebhookSync{Url: this.Url, Body: string(data)}
+
+	if err := bus.DispatchCtx(evalContext.Ctx, cmd); err != nil {
+		this.log.Error("Failed to send google chat notification", "error", err, "webhook", this.Name)
+		return err
+	}
+
+	return nil
+}
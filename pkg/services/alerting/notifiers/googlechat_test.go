@@ -0,0 +1,87 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGoogleChatNotifier(t *testing.T) {
+	Convey("Google Chat notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "googlechat",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewGoogleChatNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+          "url": "http://google.com"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "googlechat",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewGoogleChatNotifier(model)
+				googleChatNotifier := not.(*GoogleChatNotifier)
+
+				So(err, ShouldBeNil)
+				So(googleChatNotifier.Name, ShouldEqual, "ops")
+				So(googleChatNotifier.Type, ShouldEqual, "googlechat")
+				So(googleChatNotifier.Url, ShouldEqual, "http://google.com")
+			})
+		})
+
+		Convey("Sends a card", func() {
+			receiver := newWebhookReceiver()
+			defer receiver.Close()
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", receiver.URL)
+			not, _ := NewGoogleChatNotifier(&m.AlertNotification{Name: "ops", Type: "googlechat", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Id:      1,
+				Name:    "cpu",
+				Message: "cpu is high",
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "server1", Value: null.FloatFrom(95)}}
+			evalContext.ImagePublicUrl = "http://image.url"
+
+			So(not.Notify(evalContext), ShouldBeNil)
+
+			body := receiver.Json()
+			So(body.Get("previewText").MustString(), ShouldEqual, "[Alerting] cpu")
+
+			card := body.Get("cards").GetIndex(0)
+			So(card.Get("header").Get("title").MustString(), ShouldEqual, "[Alerting] cpu")
+
+			widgets := card.Get("sections").GetIndex(0).Get("widgets")
+			So(widgets.GetIndex(0).Get("textParagraph").Get("text").MustString(), ShouldEqual, "cpu is high")
+			So(widgets.GetIndex(1).Get("keyValue").Get("topLabel").MustString(), ShouldEqual, "server1")
+			So(widgets.GetIndex(1).Get("keyValue").Get("content").MustString(), ShouldEqual, "95.000")
+			So(widgets.GetIndex(2).Get("image").Get("imageUrl").MustString(), ShouldEqual, "http://image.url")
+		})
+	})
+}
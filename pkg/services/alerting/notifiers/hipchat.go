@@ -59,7 +59,7 @@ func NewHipChatNotifier(model *models.AlertNotification) (alerting.Notifier, err
 	roomId := model.Settings.Get("roomid").MustString()
 
 	return &HipChatNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Url:          url,
 		ApiKey:       apikey,
 		RoomId:       roomId,
@@ -99,6 +99,14 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 		}
 	}
 
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		fields = append(fields, map[string]interface{}{
+			"title": "Instances",
+			"value": instances,
+			"short": false,
+		})
+	}
+
 	if evalContext.Error != nil {
 		fields = append(fields, map[string]interface{}{
 			"title": "Error message",
@@ -108,7 +116,7 @@ func (this *HipChatNotifier) Notify(evalContext *alerting.EvalContext) error {
 	}
 
 	if evalContext.Rule.State != models.AlertStateOK { //dont add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetMessage(evalContext)
 	}
 	//HipChat has a set list of colors
 	var color string
@@ -39,7 +39,7 @@ func NewLINENotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	}
 
 	return &LineNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Token:        token,
 		log:          log.New("alerting.notifier.line"),
 	}, nil
@@ -72,7 +72,10 @@ func (this *LineNotifier) createAlert(evalContext *alerting.EvalContext) error {
 	}
 
 	form := url.Values{}
-	body := fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleUrl, evalContext.Rule.Message)
+	body := fmt.Sprintf("%s - %s\n%s", evalContext.GetRuleName(), ruleUrl, this.GetMessage(evalContext))
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		body += "\n" + instances
+	}
 	form.Add("message", body)
 
 	cmd := &m.SendWebhookSync{
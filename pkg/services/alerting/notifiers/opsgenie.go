@@ -2,7 +2,6 @@ package notifiers
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -50,7 +49,7 @@ func NewOpsGenieNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 	}
 
 	return &OpsGenieNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		ApiKey:       apiKey,
 		AutoClose:    autoClose,
 		log:          log.New("alerting.notifier.opsgenie"),
@@ -65,6 +64,10 @@ type OpsGenieNotifier struct {
 }
 
 func (this *OpsGenieNotifier) Notify(evalContext *alerting.EvalContext) error {
+	return notifyEach(evalContext, this.notify)
+}
+
+func (this *OpsGenieNotifier) notify(evalContext *alerting.EvalContext) error {
 	metrics.M_Alerting_Notification_Sent_OpsGenie.Inc(1)
 
 	var err error
@@ -90,10 +93,14 @@ func (this *OpsGenieNotifier) createAlert(evalContext *alerting.EvalContext) err
 
 	bodyJSON := simplejson.New()
 	bodyJSON.Set("apiKey", this.ApiKey)
-	bodyJSON.Set("message", evalContext.Rule.Name)
+	bodyJSON.Set("message", evalContext.GetRuleName())
 	bodyJSON.Set("source", "Grafana")
-	bodyJSON.Set("alias", "alertId-"+strconv.FormatInt(evalContext.Rule.Id, 10))
-	bodyJSON.Set("description", fmt.Sprintf("%s - %s\n%s", evalContext.Rule.Name, ruleUrl, evalContext.Rule.Message))
+	bodyJSON.Set("alias", evalContext.GetAlertKey())
+	description := fmt.Sprintf("%s - %s\n%s", evalContext.GetRuleName(), ruleUrl, this.GetMessage(evalContext))
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		description += "\n" + instances
+	}
+	bodyJSON.Set("description", description)
 
 	details := simplejson.New()
 	details.Set("url", ruleUrl)
@@ -122,7 +129,7 @@ func (this *OpsGenieNotifier) closeAlert(evalContext *alerting.EvalContext) erro
 
 	bodyJSON := simplejson.New()
 	bodyJSON.Set("apiKey", this.ApiKey)
-	bodyJSON.Set("alias", "alertId-"+strconv.FormatInt(evalContext.Rule.Id, 10))
+	bodyJSON.Set("alias", evalContext.GetAlertKey())
 	body, _ := bodyJSON.MarshalJSON()
 
 	cmd := &m.SendWebhookSync{
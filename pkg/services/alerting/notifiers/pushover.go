@@ -100,7 +100,7 @@ func NewPushoverNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 		return nil, alerting.ValidationError{Reason: "API token not given"}
 	}
 	return &PushoverNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		UserKey:      userKey,
 		ApiToken:     apiToken,
 		Priority:     priority,
@@ -131,13 +131,16 @@ func (this *PushoverNotifier) Notify(evalContext *alerting.EvalContext) error {
 		this.log.Error("Failed get rule link", "error", err)
 		return err
 	}
-	message := evalContext.Rule.Message
+	message := this.GetMessage(evalContext)
 	for idx, evt := range evalContext.EvalMatches {
 		message += fmt.Sprintf("\n<b>%s</b>: %v", evt.Metric, evt.Value)
 		if idx > 4 {
 			break
 		}
 	}
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		message += "\n" + instances
+	}
 	if evalContext.Error != nil {
 		message += fmt.Sprintf("\n<b>Error message:</b> %s", evalContext.Error.Error())
 	}
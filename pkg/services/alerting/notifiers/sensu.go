@@ -52,7 +52,7 @@ func NewSensuNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	}
 
 	return &SensuNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Url:          url,
 		User:         model.Settings.Get("username").MustString(),
 		Source:       model.Settings.Get("source").MustString(),
@@ -73,6 +73,10 @@ type SensuNotifier struct {
 }
 
 func (this *SensuNotifier) Notify(evalContext *alerting.EvalContext) error {
+	return notifyEach(evalContext, this.notify)
+}
+
+func (this *SensuNotifier) notify(evalContext *alerting.EvalContext) error {
 	this.log.Info("Sending sensu result")
 	metrics.M_Alerting_Notification_Sent_Sensu.Inc(1)
 
@@ -91,6 +95,8 @@ func (this *SensuNotifier) Notify(evalContext *alerting.EvalContext) error {
 	// We set it to a default output
 	bodyJSON.Set("output", "Grafana Metric Condition Met")
 	bodyJSON.Set("evalMatches", evalContext.EvalMatches)
+	bodyJSON.Set("startedInstances", evalContext.StartedInstances)
+	bodyJSON.Set("resolvedInstances", evalContext.ResolvedInstances)
 
 	if evalContext.Rule.State == "alerting" {
 		bodyJSON.Set("status", 2)
@@ -113,8 +119,8 @@ func (this *SensuNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
 	}
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("message", evalContext.Rule.Message)
+	if message := this.GetMessage(evalContext); message != "" {
+		bodyJSON.Set("message", message)
 	}
 
 	body, _ := bodyJSON.MarshalJSON()
@@ -1,10 +1,12 @@
 package notifiers
 
 import (
+	"context"
 	"testing"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -52,5 +54,29 @@ func TestSensuNotifier(t *testing.T) {
 				So(sensuNotifier.Handler, ShouldEqual, "myhandler")
 			})
 		})
+
+		Convey("Sends each alert of a group as its own check", func() {
+			receiver := newWebhookReceiver()
+			defer receiver.Close()
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", receiver.URL)
+			not, _ := NewSensuNotifier(&m.AlertNotification{Name: "sensu", Type: "sensu", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{Name: "2 alerts", State: m.AlertStateAlerting})
+			evalContext.Group = []*alerting.EvalContext{
+				alerting.NewEvalContext(context.Background(), &alerting.Rule{Id: 1, Name: "cpu", State: m.AlertStateAlerting}),
+				alerting.NewEvalContext(context.Background(), &alerting.Rule{Id: 2, Name: "disk", State: m.AlertStateOK}),
+			}
+			evalContext.GroupKey = "dashboard=0"
+
+			So(not.Notify(evalContext), ShouldBeNil)
+
+			// the receiver keeps the last check, the one of the resolved rule
+			body := receiver.Json()
+			So(body.Get("name").MustString(), ShouldEqual, "disk")
+			So(body.Get("ruleId").MustInt64(), ShouldEqual, 2)
+			So(body.Get("status").MustInt(), ShouldEqual, 0)
+		})
 	})
 }
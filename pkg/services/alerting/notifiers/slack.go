@@ -61,7 +61,7 @@ func NewSlackNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	mention := model.Settings.Get("mention").MustString()
 
 	return &SlackNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Url:          url,
 		Recipient:    recipient,
 		Mention:      mention,
@@ -100,6 +100,14 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 		}
 	}
 
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		fields = append(fields, map[string]interface{}{
+			"title": "Instances",
+			"value": instances,
+			"short": false,
+		})
+	}
+
 	if evalContext.Error != nil {
 		fields = append(fields, map[string]interface{}{
 			"title": "Error message",
@@ -110,7 +118,7 @@ func (this *SlackNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	message := this.Mention
 	if evalContext.Rule.State != m.AlertStateOK { //dont add message when going back to alert state ok.
-		message += " " + evalContext.Rule.Message
+		message += " " + this.GetMessage(evalContext)
 	}
 
 	body := map[string]interface{}{
@@ -0,0 +1,86 @@
+package notifiers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTeamsNotifier(t *testing.T) {
+	Convey("Teams notifier tests", t, func() {
+
+		Convey("Parsing alert notification from settings", func() {
+			Convey("empty settings should return error", func() {
+				json := `{ }`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "teams",
+					Settings: settingsJSON,
+				}
+
+				_, err := NewTeamsNotifier(model)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("from settings", func() {
+				json := `
+				{
+          "url": "http://google.com"
+				}`
+
+				settingsJSON, _ := simplejson.NewJson([]byte(json))
+				model := &m.AlertNotification{
+					Name:     "ops",
+					Type:     "teams",
+					Settings: settingsJSON,
+				}
+
+				not, err := NewTeamsNotifier(model)
+				teamsNotifier := not.(*TeamsNotifier)
+
+				So(err, ShouldBeNil)
+				So(teamsNotifier.Name, ShouldEqual, "ops")
+				So(teamsNotifier.Type, ShouldEqual, "teams")
+				So(teamsNotifier.Url, ShouldEqual, "http://google.com")
+			})
+		})
+
+		Convey("Sends a message card", func() {
+			receiver := newWebhookReceiver()
+			defer receiver.Close()
+
+			settingsJSON := simplejson.New()
+			settingsJSON.Set("url", receiver.URL)
+			not, _ := NewTeamsNotifier(&m.AlertNotification{Name: "ops", Type: "teams", Settings: settingsJSON})
+
+			evalContext := alerting.NewEvalContext(context.Background(), &alerting.Rule{
+				Id:      1,
+				Name:    "cpu",
+				Message: "cpu is high",
+				State:   m.AlertStateAlerting,
+			})
+			evalContext.EvalMatches = []*alerting.EvalMatch{{Metric: "server1", Value: null.FloatFrom(95)}}
+			evalContext.ImagePublicUrl = "http://image.url"
+
+			So(not.Notify(evalContext), ShouldBeNil)
+
+			body := receiver.Json()
+			So(body.Get("@type").MustString(), ShouldEqual, "MessageCard")
+			So(body.Get("title").MustString(), ShouldEqual, "[Alerting] cpu")
+			So(body.Get("themeColor").MustString(), ShouldEqual, "D63232")
+
+			section := body.Get("sections").GetIndex(0)
+			So(section.Get("text").MustString(), ShouldEqual, "cpu is high")
+			So(section.Get("facts").GetIndex(0).Get("name").MustString(), ShouldEqual, "server1")
+			So(section.Get("facts").GetIndex(0).Get("value").MustString(), ShouldEqual, "95.000")
+			So(section.Get("images").GetIndex(0).Get("image").MustString(), ShouldEqual, "http://image.url")
+		})
+	})
+}
@@ -70,7 +70,7 @@ func NewTelegramNotifier(model *m.AlertNotification) (alerting.Notifier, error)
 	}
 
 	return &TelegramNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		BotToken:     botToken,
 		ChatID:       chatId,
 		log:          log.New("alerting.notifier.telegram"),
@@ -87,7 +87,7 @@ func (this *TelegramNotifier) Notify(evalContext *alerting.EvalContext) error {
 	bodyJSON.Set("chat_id", this.ChatID)
 	bodyJSON.Set("parse_mode", "html")
 
-	message := fmt.Sprintf("<b>%s</b>\nState: %s\nMessage: %s\n", evalContext.GetNotificationTitle(), evalContext.Rule.Name, evalContext.Rule.Message)
+	message := fmt.Sprintf("<b>%s</b>\nState: %s\nMessage: %s\n", evalContext.GetNotificationTitle(), evalContext.GetRuleName(), this.GetMessage(evalContext))
 
 	ruleUrl, err := evalContext.GetRuleUrl()
 	if err == nil {
@@ -108,6 +108,9 @@ func (this *TelegramNotifier) Notify(evalContext *alerting.EvalContext) error {
 	if metrics != "" {
 		message = message + fmt.Sprintf("\n<i>Metrics:</i>%s", metrics)
 	}
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		message = message + fmt.Sprintf("\n<i>Instances:</i>\n%s", instances)
+	}
 
 	bodyJSON.Set("text", message)
 
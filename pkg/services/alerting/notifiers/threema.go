@@ -107,7 +107,7 @@ func NewThreemaNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	}
 
 	return &ThreemaNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		GatewayID:    gatewayID,
 		RecipientID:  recipientID,
 		APISecret:    apiSecret,
@@ -140,7 +140,7 @@ func (notifier *ThreemaNotifier) Notify(evalContext *alerting.EvalContext) error
 	// Build message
 	message := fmt.Sprintf("%s%s\n\n*State:* %s\n*Message:* %s\n",
 		stateEmoji, evalContext.GetNotificationTitle(),
-		evalContext.Rule.Name, evalContext.Rule.Message)
+		evalContext.GetRuleName(), notifier.GetMessage(evalContext))
 	ruleURL, err := evalContext.GetRuleUrl()
 	if err == nil {
 		message = message + fmt.Sprintf("*URL:* %s\n", ruleURL)
@@ -148,6 +148,9 @@ func (notifier *ThreemaNotifier) Notify(evalContext *alerting.EvalContext) error
 	if evalContext.ImagePublicUrl != "" {
 		message = message + fmt.Sprintf("*Image:* %s\n", evalContext.ImagePublicUrl)
 	}
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		message = message + fmt.Sprintf("*Instances:*\n%s\n", instances)
+	}
 	data.Set("text", message)
 
 	// Prepare and send request
@@ -52,7 +52,7 @@ func NewVictoropsNotifier(model *models.AlertNotification) (alerting.Notifier, e
 	}
 
 	return &VictoropsNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		URL:          url,
 		AutoResolve:  autoResolve,
 		log:          log.New("alerting.notifier.victorops"),
@@ -71,6 +71,10 @@ type VictoropsNotifier struct {
 
 // Notify sends notification to Victorops via POST to URL endpoint
 func (this *VictoropsNotifier) Notify(evalContext *alerting.EvalContext) error {
+	return notifyEach(evalContext, this.notify)
+}
+
+func (this *VictoropsNotifier) notify(evalContext *alerting.EvalContext) error {
 	this.log.Info("Executing victorops notification", "ruleId", evalContext.Rule.Id, "notification", this.Name)
 	metrics.M_Alerting_Notification_Sent_Victorops.Inc(1)
 
@@ -98,6 +102,14 @@ func (this *VictoropsNotifier) Notify(evalContext *alerting.EvalContext) error {
 		}
 	}
 
+	if instances := evalContext.GetInstancesText(); instances != "" {
+		fields = append(fields, map[string]interface{}{
+			"title": "Instances",
+			"value": instances,
+			"short": false,
+		})
+	}
+
 	if evalContext.Error != nil {
 		fields = append(fields, map[string]interface{}{
 			"title": "Error message",
@@ -120,7 +132,7 @@ func (this *VictoropsNotifier) Notify(evalContext *alerting.EvalContext) error {
 	bodyJSON.Set("entity_id", evalContext.Rule.Name)
 	bodyJSON.Set("timestamp", time.Now().Unix())
 	bodyJSON.Set("state_start_time", evalContext.StartTime.Unix())
-	bodyJSON.Set("state_message", evalContext.Rule.Message)
+	bodyJSON.Set("state_message", this.GetMessage(evalContext))
 	bodyJSON.Set("monitoring_tool", "Grafana v"+setting.BuildVersion)
 	bodyJSON.Set("alert_url", ruleUrl)
 
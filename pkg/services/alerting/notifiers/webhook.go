@@ -48,7 +48,7 @@ func NewWebHookNotifier(model *m.AlertNotification) (alerting.Notifier, error) {
 	}
 
 	return &WebhookNotifier{
-		NotifierBase: NewNotifierBase(model.Id, model.IsDefault, model.Name, model.Type, model.Settings),
+		NotifierBase: NewNotifierBase(model),
 		Url:          url,
 		User:         model.Settings.Get("username").MustString(),
 		Password:     model.Settings.Get("password").MustString(),
@@ -76,6 +76,8 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 	bodyJSON.Set("ruleName", evalContext.Rule.Name)
 	bodyJSON.Set("state", evalContext.Rule.State)
 	bodyJSON.Set("evalMatches", evalContext.EvalMatches)
+	bodyJSON.Set("startedInstances", evalContext.StartedInstances)
+	bodyJSON.Set("resolvedInstances", evalContext.ResolvedInstances)
 
 	ruleUrl, err := evalContext.GetRuleUrl()
 	if err == nil {
@@ -86,8 +88,12 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 		bodyJSON.Set("imageUrl", evalContext.ImagePublicUrl)
 	}
 
-	if evalContext.Rule.Message != "" {
-		bodyJSON.Set("message", evalContext.Rule.Message)
+	if message := this.GetMessage(evalContext); message != "" {
+		bodyJSON.Set("message", message)
+	}
+
+	if evalContext.Group != nil {
+		bodyJSON.Set("alerts", groupAlerts(evalContext))
 	}
 
 	body, _ := bodyJSON.MarshalJSON()
@@ -107,3 +113,22 @@ func (this *WebhookNotifier) Notify(evalContext *alerting.EvalContext) error {
 
 	return nil
 }
+
+// groupAlerts lists the alerts of a group of notifications.
+func groupAlerts(evalContext *alerting.EvalContext) []map[string]interface{} {
+	alerts := make([]map[string]interface{}, 0, len(evalContext.Group))
+	for _, member := range evalContext.Group {
+		alert := map[string]interface{}{
+			"ruleId":      member.Rule.Id,
+			"ruleName":    member.GetRuleName(),
+			"state":       member.Rule.State,
+			"message":     member.GetMessage(),
+			"evalMatches": member.EvalMatches,
+		}
+		if ruleUrl, err := member.GetRuleUrl(); err == nil {
+			alert["ruleUrl"] = ruleUrl
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
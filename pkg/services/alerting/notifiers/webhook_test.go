@@ -1,8 +1,14 @@
 package notifiers
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/grafana/grafana/pkg/bus"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
 	. "github.com/smartystreets/goconvey/convey"
@@ -50,3 +56,37 @@ func TestWebhookNotifier(t *testing.T) {
 		})
 	})
 }
+
+// webhookReceiver is a test server that the webhooks of notifiers are sent
+// to. It keeps the path and body of the last request.
+type webhookReceiver struct {
+	*httptest.Server
+	path string
+	body []byte
+}
+
+func newWebhookReceiver() *webhookReceiver {
+	receiver := &webhookReceiver{}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		receiver.path = req.URL.Path
+		receiver.body, _ = ioutil.ReadAll(req.Body)
+	}))
+
+	bus.AddCtxHandler("test", func(ctx context.Context, cmd *m.SendWebhookSync) error {
+		resp, err := http.Post(cmd.Url, "application/json", strings.NewReader(cmd.Body))
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	})
+
+	return receiver
+}
+
+func (r *webhookReceiver) Json() *simplejson.Json {
+	json, err := simplejson.NewJson(r.body)
+	if err != nil {
+		return simplejson.New()
+	}
+	return json
+}
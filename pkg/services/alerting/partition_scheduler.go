@@ -0,0 +1,112 @@
+package alerting
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/log"
+)
+
+// partitionScheduler runs the rules the cluster assigned to this node for one
+// interval. It ticks a SchedulerImpl over every second of the interval, so a
+// rule keeps its frequency and offset just like it does without clustering.
+type partitionScheduler struct {
+	lock      sync.Mutex
+	scheduler *SchedulerImpl
+	start     int64 // first second of the interval
+	end       int64 // first second after the interval
+	lastTick  int64
+	log       log.Logger
+}
+
+func newPartitionScheduler() *partitionScheduler {
+	return &partitionScheduler{
+		scheduler: &SchedulerImpl{
+			jobs: make(map[int64]*Job, 0),
+			log:  log.New("alerting.scheduler"),
+		},
+		log: log.New("alerting.partitionScheduler"),
+	}
+}
+
+// Update replaces the rules run by node nodeId for the given partition with
+// the ones accepted by owns and makes [start, end) the interval to run them in.
+// It returns the number of rules owned by the node.
+func (p *partitionScheduler) Update(rules []*Rule, owns func(rule *Rule) bool, nodeId string, partition string, start, end int64) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	prevJobs := p.scheduler.jobs
+	continued := p.end == start
+	p.scheduler.update(rules, owns)
+	for id, job := range p.scheduler.jobs {
+		// a rule taken over from another node, or from an interval this node
+		// did not run, may have been due right before the interval and still
+		// be waiting for its offset
+		if !continued || prevJobs[id] == nil {
+			job.OffsetWait = isOffsetPending(job, start)
+			if job.OffsetWait {
+				job.ScheduledAt = lastDueBefore(job, start)
+			}
+		}
+		job.NodeId = nodeId
+		job.Partition = partition
+	}
+
+	p.start = start
+	p.end = end
+	if p.lastTick < start-1 {
+		p.lastTick = start - 1
+	}
+	return len(p.scheduler.jobs)
+}
+
+// Refresh replaces the rules this node runs with their current definitions and
+// returns how many it still runs. Deleted rules stop running, rules the node
+// does not run yet are only picked up by the next Update.
+func (p *partitionScheduler) Refresh(rules []*Rule) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	prevJobs := p.scheduler.jobs
+	p.scheduler.update(rules, func(rule *Rule) bool {
+		return prevJobs[rule.Id] != nil
+	})
+	return len(p.scheduler.jobs)
+}
+
+// Tick runs the scheduler for every second of the interval up to tickTime that
+// has not been run yet. Ticks that come before the interval is known are
+// caught up on once it is.
+func (p *partitionScheduler) Tick(tickTime time.Time, execQueue chan *Job) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := tickTime.Unix()
+	for t := p.lastTick + 1; t <= now && t < p.end; t++ {
+		p.scheduler.Tick(time.Unix(t, 0), execQueue)
+		p.lastTick = t
+	}
+}
+
+// ScheduledUntil returns the end of the interval this node is running rules for.
+func (p *partitionScheduler) ScheduledUntil() int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.end
+}
+
+// isOffsetPending reports whether the job's last due time before start is
+// still waiting for its offset at start.
+func isOffsetPending(job *Job, start int64) bool {
+	if job.Rule.Frequency <= 0 || start <= 0 {
+		return false
+	}
+	lastDue := lastDueBefore(job, start)
+	nextRun := (lastDue/job.Offset + 1) * job.Offset
+	return nextRun >= start
+}
+
+func lastDueBefore(job *Job, start int64) int64 {
+	return ((start - 1) / job.Rule.Frequency) * job.Rule.Frequency
+}
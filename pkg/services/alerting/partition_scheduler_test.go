@@ -0,0 +1,102 @@
+package alerting
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/hashring"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPartitionScheduler(t *testing.T) {
+	Convey("Test partition scheduler", t, func() {
+		rules := make([]*Rule, 0)
+		for i := 1; i <= 90; i++ {
+			frequency := int64(60)
+			if i%3 == 0 {
+				frequency = 30
+			}
+			rules = append(rules, &Rule{Id: int64(i), Frequency: frequency})
+		}
+		start := int64(1493233560)
+
+		Convey("Rules run once per due time while nodes join and leave", func() {
+			schedulers := map[string]*partitionScheduler{
+				"node1:3000": newPartitionScheduler(),
+				"node2:3000": newPartitionScheduler(),
+				"node3:3000": newPartitionScheduler(),
+			}
+			membership := [][]string{
+				{"node1:3000", "node2:3000"},
+				{"node1:3000", "node2:3000", "node3:3000"},
+				{"node2:3000", "node3:3000"},
+				{"node1:3000", "node2:3000", "node3:3000"},
+			}
+			execQueue := make(chan *Job, 1000)
+			counts := make(map[int64]int)
+			claims := make(map[string]bool)
+			for i, nodes := range membership {
+				intervalStart := start + int64(i)*60
+				ring := hashring.New(200, nodes...)
+				for _, node := range nodes {
+					nodeId := node
+					schedulers[nodeId].Update(rules, func(rule *Rule) bool {
+						return ring.Get(strconv.FormatInt(rule.Id, 10)) == nodeId
+					}, nodeId, "0", intervalStart, intervalStart+60)
+				}
+				for t := intervalStart; t < intervalStart+60; t++ {
+					for _, scheduler := range schedulers {
+						scheduler.Tick(time.Unix(t, 0), execQueue)
+					}
+					for len(execQueue) > 0 {
+						job := <-execQueue
+						counts[job.Rule.Id]++
+						So(job.ScheduledAt%job.Rule.Frequency, ShouldEqual, 0)
+						claim := fmt.Sprintf("%v/%v", job.Rule.Id, job.ScheduledAt)
+						So(claims[claim], ShouldBeFalse)
+						claims[claim] = true
+					}
+				}
+			}
+
+			for _, rule := range rules {
+				So(counts[rule.Id], ShouldEqual, len(membership)*60/int(rule.Frequency))
+			}
+		})
+
+		Convey("Node that is not given an interval stops running rules", func() {
+			scheduler := newPartitionScheduler()
+			execQueue := make(chan *Job, 1000)
+			scheduler.Update(rules, nil, "node1:3000", "0", start, start+60)
+			scheduler.Tick(time.Unix(start+59, 0), execQueue)
+			So(len(execQueue), ShouldBeGreaterThan, 0)
+			So(scheduler.ScheduledUntil(), ShouldEqual, start+60)
+
+			for len(execQueue) > 0 {
+				<-execQueue
+			}
+			scheduler.Tick(time.Unix(start+119, 0), execQueue)
+			So(len(execQueue), ShouldEqual, 0)
+		})
+
+		Convey("Refresh updates the rules the node runs", func() {
+			scheduler := newPartitionScheduler()
+			scheduler.Update(rules, func(rule *Rule) bool {
+				return rule.Id <= 10
+			}, "node1:3000", "0", start, start+60)
+
+			refreshed := make([]*Rule, 0)
+			for _, rule := range rules[1:] {
+				refreshed = append(refreshed, &Rule{Id: rule.Id, Frequency: 10})
+			}
+			So(scheduler.Refresh(refreshed), ShouldEqual, 9)
+			So(scheduler.scheduler.jobs[1], ShouldBeNil)
+			So(scheduler.scheduler.jobs[2].Rule.Frequency, ShouldEqual, 10)
+			So(scheduler.scheduler.jobs[2].NodeId, ShouldEqual, "node1:3000")
+			So(scheduler.scheduler.jobs[2].Partition, ShouldEqual, "0")
+			So(scheduler.scheduler.jobs[11], ShouldBeNil)
+		})
+	})
+}
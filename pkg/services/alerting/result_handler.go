@@ -42,7 +42,18 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 		annotationData.Set("noData", true)
 	}
 
+	if silences, err := evalContext.GetSilences(); err != nil {
+		handler.log.Error("Failed to get alert silences", "alertId", evalContext.Rule.Id, "error", err)
+	} else if len(silences) > 0 {
+		silencedBy := make([]int64, 0)
+		for _, silence := range silences {
+			silencedBy = append(silencedBy, silence.Id)
+		}
+		annotationData.Set("silencedBy", silencedBy)
+	}
+
 	countStateResult(evalContext.Rule.State)
+	instancesChanged := handler.updateInstances(evalContext)
 	if evalContext.ShouldUpdateAlertState() {
 		handler.log.Info("New state change", "alertId", evalContext.Rule.Id, "newState", evalContext.Rule.State, "prev state", evalContext.PrevAlertState)
 
@@ -66,6 +77,8 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 			}
 
 			handler.log.Error("Failed to save state", "error", err)
+		} else {
+			evalContext.Rule.NewStateDate = time.Now()
 		}
 
 		// save annotation
@@ -88,6 +101,22 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 			handler.log.Error("Failed to save annotation for new alert state", "error", err)
 		}
 
+		historyCmd := &m.SaveAlertStateHistoryCommand{
+			OrgId:       evalContext.Rule.OrgId,
+			AlertId:     evalContext.Rule.Id,
+			DashboardId: evalContext.Rule.DashboardId,
+			PanelId:     evalContext.Rule.PanelId,
+			AlertName:   evalContext.Rule.Name,
+			NodeId:      evalContext.NodeId,
+			PrevState:   evalContext.PrevAlertState,
+			NewState:    evalContext.Rule.State,
+			Data:        annotationData,
+			Epoch:       item.Epoch,
+		}
+		if err := bus.Dispatch(historyCmd); err != nil {
+			handler.log.Error("Failed to save alert state history", "error", err)
+		}
+
 		if evalContext.ShouldSendNotification() {
 			handler.notifier.Send(evalContext)
 		}
@@ -99,10 +128,47 @@ func (handler *DefaultResultHandler) Handle(evalContext *EvalContext) error {
 		if err := bus.Dispatch(cmd); err != nil {
 			handler.log.Error("Failed to update eval date for alert", "error", err)
 		}
+
+		// notifiers send the series that started or resolved, and reminders
+		// while the rule stays alerting
+		if evalContext.Rule.State == m.AlertStateAlerting {
+			if instancesChanged {
+				handler.log.Info("New instance state change", "alertId", evalContext.Rule.Id,
+					"started", len(evalContext.StartedInstances), "resolved", len(evalContext.ResolvedInstances))
+			}
+			handler.notifier.Send(evalContext)
+		}
 	}
 	return nil
 }
 
+// updateInstances saves the per series state of the alert and reports if any
+// series started or stopped alerting.
+func (handler *DefaultResultHandler) updateInstances(evalContext *EvalContext) bool {
+	query := &m.GetAlertInstancesQuery{OrgId: evalContext.Rule.OrgId, AlertId: evalContext.Rule.Id}
+	if err := bus.Dispatch(query); err != nil {
+		handler.log.Error("Failed to get alert instances", "alertId", evalContext.Rule.Id, "error", err)
+		return false
+	}
+
+	changed := diffInstances(evalContext, query.Result)
+	if len(changed) == 0 {
+		return false
+	}
+
+	cmd := &m.SaveAlertInstancesCommand{
+		OrgId:     evalContext.Rule.OrgId,
+		AlertId:   evalContext.Rule.Id,
+		Instances: changed,
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		handler.log.Error("Failed to save alert instances", "alertId", evalContext.Rule.Id, "error", err)
+		return false
+	}
+
+	return len(evalContext.StartedInstances) > 0 || len(evalContext.ResolvedInstances) > 0
+}
+
 func countStateResult(state m.AlertStateType) {
 	switch state {
 	case m.AlertStatePending:
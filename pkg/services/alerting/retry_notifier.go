@@ -0,0 +1,245 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// retryNotifier sends the notifications of a notifier again when they fail,
+// waiting twice as long before each retry. A notification that fails all
+// attempts is stored in the notification log.
+type retryNotifier struct {
+	Notifier
+	maxAttempts int
+	backoff     time.Duration
+	timeout     time.Duration
+	log         log.Logger
+}
+
+func newRetryNotifier(notifier Notifier) *retryNotifier {
+	maxAttempts := setting.AlertingNotificationMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	return &retryNotifier{
+		Notifier:    notifier,
+		maxAttempts: maxAttempts,
+		backoff:     time.Duration(setting.AlertingNotificationRetryBackoff) * time.Second,
+		timeout:     time.Duration(setting.AlertingNotificationTimeout) * time.Second,
+		log:         log.New("alerting.notifier"),
+	}
+}
+
+func (n *retryNotifier) Notify(evalContext *EvalContext) error {
+	attempts, err := n.notify(evalContext)
+	if err != nil && !evalContext.IsTestRun {
+		n.saveFailedNotification(evalContext, attempts, err)
+	}
+	return err
+}
+
+// notify returns the number of attempts it took. Notifications of an alert
+// evaluation are sent while the evaluation runs, so the retries stop when the
+// evaluation times out or is cancelled, and the notification is stored in the
+// notification log instead.
+func (n *retryNotifier) notify(evalContext *EvalContext) (int, error) {
+	ctx := evalContext.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	backoff := n.backoff
+	for attempt := 1; ; attempt++ {
+		err := n.attempt(ctx, evalContext)
+		if err == nil || attempt >= n.maxAttempts {
+			return attempt, err
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			n.log.Warn("Failed to send notification, no time left to retry", "alertId", evalContext.Rule.Id,
+				"notifierId", n.GetNotifierId(), "attempt", attempt, "error", err)
+			return attempt, err
+		}
+
+		n.log.Warn("Failed to send notification, retrying", "alertId", evalContext.Rule.Id, "notifierId", n.GetNotifierId(),
+			"attempt", attempt, "retryIn", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// attempt sends the notification with the notification timeout, within the
+// time that is left of ctx.
+func (n *retryNotifier) attempt(ctx context.Context, evalContext *EvalContext) error {
+	if n.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.timeout)
+		defer cancel()
+	}
+
+	attemptContext := *evalContext
+	attemptContext.Ctx = ctx
+	return n.Notifier.Notify(&attemptContext)
+}
+
+func (n *retryNotifier) saveFailedNotification(evalContext *EvalContext, attempts int, notifyErr error) {
+	cmd := &m.SaveAlertNotificationLogCommand{
+		OrgId:      evalContext.Rule.OrgId,
+		AlertId:    evalContext.Rule.Id,
+		NotifierId: n.GetNotifierId(),
+		Payload:    newNotificationPayload(evalContext),
+		Error:      notifyErr.Error(),
+		Attempts:   attempts,
+	}
+	if err := bus.Dispatch(cmd); err != nil {
+		n.log.Error("Failed to save failed notification", "alertId", evalContext.Rule.Id, "notifierId", n.GetNotifierId(), "error", err)
+	}
+}
+
+// newNotificationPayload keeps the state of the evaluation that a
+// notification is built from, with the title and message it was sent with.
+// The payload of a group of notifications has the payloads of its alerts.
+func newNotificationPayload(evalContext *EvalContext) *simplejson.Json {
+	payload := simplejson.New()
+	if evalContext.Group != nil {
+		alerts := make([]interface{}, 0, len(evalContext.Group))
+		for _, member := range evalContext.Group {
+			alert := newNotificationPayload(member)
+			alert.Set("alertId", member.Rule.Id)
+			alerts = append(alerts, alert.Interface())
+		}
+		payload.Set("groupKey", evalContext.GroupKey)
+		payload.Set("alerts", alerts)
+	}
+	payload.Set("title", evalContext.GetNotificationTitle())
+	payload.Set("message", evalContext.GetMessage())
+	payload.Set("state", evalContext.Rule.State)
+	payload.Set("prevState", evalContext.PrevAlertState)
+	payload.Set("firing", evalContext.Firing)
+	payload.Set("noData", evalContext.NoDataFound)
+	payload.Set("evalMatches", evalContext.EvalMatches)
+	payload.Set("startedInstances", evalContext.StartedInstances)
+	payload.Set("resolvedInstances", evalContext.ResolvedInstances)
+	payload.Set("imageUrl", evalContext.ImagePublicUrl)
+	if evalContext.Error != nil {
+		payload.Set("error", evalContext.Error.Error())
+	}
+
+	// store plain JSON, as the eval matches are read back from it
+	if data, err := payload.Encode(); err == nil {
+		if decoded, err := simplejson.NewJson(data); err == nil {
+			return decoded
+		}
+	}
+	return payload
+}
+
+// ReplayNotificationCommand sends a notification from the notification log
+// again through its notification channel.
+type ReplayNotificationCommand struct {
+	Id    int64
+	OrgId int64
+}
+
+func init() {
+	bus.AddHandler("alerting", handleReplayNotificationCommand)
+}
+
+func handleReplayNotificationCommand(cmd *ReplayNotificationCommand) error {
+	logQuery := &m.GetAlertNotificationLogByIdQuery{Id: cmd.Id, OrgId: cmd.OrgId}
+	if err := bus.Dispatch(logQuery); err != nil {
+		return err
+	}
+	entry := logQuery.Result
+
+	evalContext, err := newEvalContextFromLog(entry)
+	if err != nil {
+		return err
+	}
+
+	notificationQuery := &m.GetAlertNotificationsQuery{Id: entry.NotifierId, OrgId: cmd.OrgId}
+	if err := bus.Dispatch(notificationQuery); err != nil {
+		return err
+	}
+	if notificationQuery.Result == nil {
+		return ValidationError{Reason: "The notification channel of the notification no longer exists"}
+	}
+
+	notifier, err := newNotificationService().createNotifierFor(notificationQuery.Result)
+	if err != nil {
+		return err
+	}
+
+	if _, err := newRetryNotifier(notifier).notify(evalContext); err != nil {
+		return err
+	}
+
+	return bus.Dispatch(&m.MarkAlertNotificationLogReplayedCommand{Id: entry.Id, OrgId: cmd.OrgId, ReplayedAt: time.Now()})
+}
+
+// newEvalContextFromLog rebuilds the evaluation of a notification log entry,
+// or the evaluations of the alerts of a group of notifications.
+func newEvalContextFromLog(entry *m.AlertNotificationLog) (*EvalContext, error) {
+	groupKey := entry.Payload.Get("groupKey").MustString()
+	if groupKey == "" {
+		return newEvalContextFromAlert(entry.AlertId, entry.Payload)
+	}
+
+	alerts := entry.Payload.Get("alerts")
+	members := make([]*EvalContext, 0)
+	for i := range alerts.MustArray() {
+		alert := alerts.GetIndex(i)
+		member, err := newEvalContextFromAlert(alert.Get("alertId").MustInt64(), alert)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if len(members) == 0 {
+		return nil, ValidationError{Reason: "The notification has no alerts"}
+	}
+	return newGroupEvalContext(groupKey, members), nil
+}
+
+func newEvalContextFromAlert(alertId int64, payload *simplejson.Json) (*EvalContext, error) {
+	alertQuery := &m.GetAlertByIdQuery{Id: alertId}
+	if err := bus.Dispatch(alertQuery); err != nil {
+		return nil, err
+	}
+	rule, err := NewRuleFromDBAlert(alertQuery.Result)
+	if err != nil {
+		return nil, err
+	}
+	return newEvalContextFromPayload(rule, payload), nil
+}
+
+func newEvalContextFromPayload(rule *Rule, payload *simplejson.Json) *EvalContext {
+	rule.State = m.AlertStateType(payload.Get("state").MustString(string(rule.State)))
+
+	evalContext := NewEvalContext(context.Background(), rule)
+	evalContext.PrevAlertState = m.AlertStateType(payload.Get("prevState").MustString())
+	evalContext.Firing = payload.Get("firing").MustBool()
+	evalContext.NoDataFound = payload.Get("noData").MustBool()
+	evalContext.EvalMatches = parseEvalMatches(payload.Get("evalMatches"))
+	evalContext.StartedInstances = parseEvalMatches(payload.Get("startedInstances"))
+	evalContext.ResolvedInstances = parseEvalMatches(payload.Get("resolvedInstances"))
+	evalContext.ImagePublicUrl = payload.Get("imageUrl").MustString()
+	if text := payload.Get("error").MustString(); text != "" {
+		evalContext.Error = errors.New(text)
+	}
+	return evalContext
+}
@@ -0,0 +1,91 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type failingNotifier struct {
+	FakeNotifier
+	failures int
+	calls    int
+}
+
+func (n *failingNotifier) Notify(evalContext *EvalContext) error {
+	n.calls++
+	if n.calls <= n.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestRetryNotifier(t *testing.T) {
+	Convey("Test retry notifier", t, func() {
+		var saved *m.SaveAlertNotificationLogCommand
+		bus.AddHandler("test", func(cmd *m.SaveAlertNotificationLogCommand) error {
+			saved = cmd
+			return nil
+		})
+
+		evalContext := NewEvalContext(context.TODO(), &Rule{Id: 1, OrgId: 1, Name: "cpu", State: m.AlertStateAlerting})
+		evalContext.Firing = true
+		evalContext.EvalMatches = []*EvalMatch{{Metric: "cpu", Value: null.FloatFrom(95), Tags: map[string]string{"host": "server1"}}}
+		inner := &failingNotifier{}
+		notifier := &retryNotifier{Notifier: inner, maxAttempts: 3, log: log.New("alerting.notifier")}
+
+		Convey("Notification is sent again until it succeeds", func() {
+			inner.failures = 2
+			So(notifier.Notify(evalContext), ShouldBeNil)
+			So(inner.calls, ShouldEqual, 3)
+			So(saved, ShouldBeNil)
+		})
+
+		Convey("Notification that fails all attempts is stored", func() {
+			inner.failures = 3
+			So(notifier.Notify(evalContext), ShouldNotBeNil)
+			So(inner.calls, ShouldEqual, 3)
+			So(saved, ShouldNotBeNil)
+			So(saved.Attempts, ShouldEqual, 3)
+			So(saved.Error, ShouldEqual, "connection refused")
+			So(saved.Payload.Get("title").MustString(), ShouldEqual, "[Alerting] cpu")
+
+			Convey("Stored notification can be sent again with the same state", func() {
+				replayed := newEvalContextFromPayload(&Rule{Id: 1, OrgId: 1, Name: "cpu", State: m.AlertStateOK}, saved.Payload)
+				So(replayed.Rule.State, ShouldEqual, m.AlertStateAlerting)
+				So(replayed.Firing, ShouldBeTrue)
+				So(len(replayed.EvalMatches), ShouldEqual, 1)
+				So(replayed.EvalMatches[0].Tags["host"], ShouldEqual, "server1")
+				So(replayed.EvalMatches[0].Value.Float64, ShouldEqual, 95)
+			})
+		})
+
+		Convey("Retries stop when the evaluation has no time left", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			evalContext.Ctx = ctx
+			notifier.backoff = time.Second
+			inner.failures = 3
+
+			started := time.Now()
+			So(notifier.Notify(evalContext), ShouldNotBeNil)
+			So(time.Since(started), ShouldBeLessThan, time.Second)
+			So(inner.calls, ShouldEqual, 1)
+			So(saved.Attempts, ShouldEqual, 1)
+		})
+
+		Convey("Failed test notifications are not stored", func() {
+			inner.failures = 3
+			evalContext.IsTestRun = true
+			So(notifier.Notify(evalContext), ShouldNotBeNil)
+			So(saved, ShouldBeNil)
+		})
+	})
+}
@@ -19,12 +19,16 @@ type Rule struct {
 	Frequency           int64
 	Name                string
 	Message             string
+	Severity            string
+	Labels              map[string]string // matched by the routing policies of the org
 	NoDataState         m.NoDataOption
 	ExecutionErrorState m.ExecutionErrorOption
 	State               m.AlertStateType
 	Conditions          []Condition
 	Notifications       []int64
 	EvalDate            time.Time
+	For                 time.Duration // how long the conditions must fire before the rule is alerting
+	NewStateDate        time.Time
 }
 
 type ValidationError struct {
@@ -99,11 +103,38 @@ func NewRuleFromDBAlert(ruleDef *m.Alert) (*Rule, error) {
 	model.PanelId = ruleDef.PanelId
 	model.Name = ruleDef.Name
 	model.Message = ruleDef.Message
+	model.Severity = ruleDef.Severity
 	model.Frequency = ruleDef.Frequency
 	model.State = ruleDef.State
 	model.NoDataState = m.NoDataOption(ruleDef.Settings.Get("noDataState").MustString("no_data"))
 	model.ExecutionErrorState = m.ExecutionErrorOption(ruleDef.Settings.Get("executionErrorState").MustString("alerting"))
 	model.EvalDate = ruleDef.EvalDate
+	model.NewStateDate = ruleDef.NewStateDate
+
+	model.Labels = make(map[string]string)
+	for key, value := range ruleDef.Settings.Get("labels").MustMap() {
+		if text, ok := value.(string); !ok {
+			return nil, ValidationError{Reason: "Label values must be strings", DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+		} else {
+			model.Labels[key] = text
+		}
+	}
+
+	if forString := ruleDef.Settings.Get("for").MustString(); forString != "" {
+		forSeconds, err := getTimeDurationStringToSeconds(forString)
+		if err != nil {
+			return nil, ValidationError{Reason: "Could not parse for", DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+		}
+		model.For = time.Duration(forSeconds) * time.Second
+	}
+
+	if err := validateRuleTemplate(model.Name); err != nil {
+		return nil, ValidationError{Err: err, Reason: "Invalid name template", DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+	}
+
+	if err := validateRuleTemplate(model.Message); err != nil {
+		return nil, ValidationError{Err: err, Reason: "Invalid message template", DashboardId: model.DashboardId, Alertid: model.Id, PanelId: model.PanelId}
+	}
 
 	for _, v := range ruleDef.Settings.Get("notifications").MustArray() {
 		jsonModel := simplejson.NewFromAny(v)
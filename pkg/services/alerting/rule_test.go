@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
@@ -88,6 +89,36 @@ func TestAlertRuleModel(t *testing.T) {
 			Convey("Can read notifications", func() {
 				So(len(alertRule.Notifications), ShouldEqual, 2)
 			})
+
+			Convey("Has no for duration by default", func() {
+				So(alertRule.For, ShouldEqual, 0)
+			})
+
+			Convey("Can read for duration", func() {
+				alertJSON.Set("for", "5m")
+				alertRule, err := NewRuleFromDBAlert(alert)
+				So(err, ShouldBeNil)
+				So(alertRule.For, ShouldEqual, 5*time.Minute)
+			})
+
+			Convey("Returns a validation error for an invalid for duration", func() {
+				alertJSON.Set("for", "five minutes")
+				_, err := NewRuleFromDBAlert(alert)
+				So(err, ShouldHaveSameTypeAs, ValidationError{})
+			})
+
+			Convey("Accepts a message that is not a valid template", func() {
+				alert.Message = "cpu {{ is high"
+				alertRule, err := NewRuleFromDBAlert(alert)
+				So(err, ShouldBeNil)
+				So(alertRule.Message, ShouldEqual, "cpu {{ is high")
+			})
+
+			Convey("Returns a validation error for an invalid message template", func() {
+				alert.Message = "{{.Unknown}}"
+				_, err := NewRuleFromDBAlert(alert)
+				So(err, ShouldHaveSameTypeAs, ValidationError{})
+			})
 		})
 	})
 }
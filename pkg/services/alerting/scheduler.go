@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
 	"github.com/grafana/grafana/pkg/models"
 )
 
@@ -21,11 +22,22 @@ func NewScheduler() Scheduler {
 }
 
 func (s *SchedulerImpl) Update(rules []*Rule) {
+	s.update(rules, nil)
+}
+
+// update keeps jobs for the rules accepted by owns, or for all rules when owns
+// is nil. Offsets are spread over all rules so that every node in a cluster
+// computes the same offset for a rule.
+func (s *SchedulerImpl) update(rules []*Rule, owns func(rule *Rule) bool) {
 	s.log.Debug("Scheduling update", "ruleCount", len(rules))
 
 	jobs := make(map[int64]*Job, 0)
 
 	for i, rule := range rules {
+		if owns != nil && !owns(rule) {
+			continue
+		}
+
 		var job *Job
 		if s.jobs[rule.Id] != nil {
 			job = s.jobs[rule.Id]
@@ -63,6 +75,7 @@ func (s *SchedulerImpl) Tick(tickTime time.Time, execQueue chan *Job) {
 		}
 
 		if now%job.Rule.Frequency == 0 {
+			job.ScheduledAt = now
 			if job.Offset > 0 {
 				job.OffsetWait = true
 			} else {
@@ -74,5 +87,8 @@ func (s *SchedulerImpl) Tick(tickTime time.Time, execQueue chan *Job) {
 
 func (s *SchedulerImpl) enque(job *Job, execQueue chan *Job) {
 	s.log.Debug("Scheduler: Putting job on to exec queue", "name", job.Rule.Name, "id", job.Rule.Id)
+	if job.NodeId != "" {
+		metrics.M_Clustering_Scheduled_Jobs.With(job.Partition).Inc(1)
+	}
 	execQueue <- job
 }
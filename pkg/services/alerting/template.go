@@ -0,0 +1,237 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"text/template"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/null"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+// TemplateData is what the rule name, the rule message and the template of a
+// notification channel can refer to, like {{.State}} or
+// {{range .EvalMatches}}{{.Metric}}: {{.Value}}{{end}}. The template of a
+// channel that groups notifications can list the alerts of a group with
+// {{range .Alerts}}{{.RuleName}}{{end}}.
+type TemplateData struct {
+	RuleId            int64
+	RuleName          string
+	Message           string
+	State             m.AlertStateType
+	PrevState         m.AlertStateType
+	EvalMatches       []*EvalMatch
+	StartedInstances  []*EvalMatch
+	ResolvedInstances []*EvalMatch
+	Error             string
+	RuleUrl           string
+	ImageUrl          string
+	Alerts            []*TemplateData // the alerts of a group of notifications
+}
+
+// PreviewTemplateCommand renders the template with the state of the alert
+// AlertId, or with sample data if it is 0.
+type PreviewTemplateCommand struct {
+	OrgId    int64
+	AlertId  int64
+	Template string
+
+	Result string
+}
+
+func init() {
+	bus.AddHandler("alerting", handlePreviewTemplateCommand)
+}
+
+func renderTemplate(text string, data *TemplateData) (string, error) {
+	tmpl, err := template.New("alert").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ValidateTemplate renders the template with sample data, so both syntax
+// errors and unknown fields are reported.
+func ValidateTemplate(text string) error {
+	_, err := renderTemplate(text, newSampleTemplateData())
+	return err
+}
+
+// validateRuleTemplate validates the name or message of a rule that is a
+// template. Text that does not parse as one, like text with a literal {{, is
+// sent as written, so it is accepted.
+func validateRuleTemplate(text string) error {
+	if _, err := template.New("alert").Parse(text); err != nil {
+		return nil
+	}
+	return ValidateTemplate(text)
+}
+
+func newSampleTemplateData() *TemplateData {
+	matches := []*EvalMatch{
+		{Metric: "cpu", Value: null.FloatFrom(98.765), Tags: map[string]string{"host": "server1"}},
+	}
+
+	data := &TemplateData{
+		RuleId:            1,
+		RuleName:          "High CPU",
+		Message:           "CPU usage is high",
+		State:             m.AlertStateAlerting,
+		PrevState:         m.AlertStateOK,
+		EvalMatches:       matches,
+		StartedInstances:  matches,
+		ResolvedInstances: make([]*EvalMatch, 0),
+		RuleUrl:           "http://localhost:3000/dashboard/db/sample?fullscreen&edit&tab=alert&panelId=1&orgId=1",
+		ImageUrl:          "http://localhost:3000/public/img/sample.png",
+		Alerts:            make([]*TemplateData, 0),
+	}
+
+	alert := *data
+	data.Alerts = append(data.Alerts, &alert)
+	return data
+}
+
+// GetTemplateData returns the state of the evaluation for templates. RuleName
+// and Message are the rendered rule name and message.
+func (c *EvalContext) GetTemplateData() *TemplateData {
+	data := &TemplateData{
+		RuleId:            c.Rule.Id,
+		RuleName:          c.Rule.Name,
+		State:             c.Rule.State,
+		PrevState:         c.PrevAlertState,
+		EvalMatches:       c.EvalMatches,
+		StartedInstances:  c.StartedInstances,
+		ResolvedInstances: c.ResolvedInstances,
+		ImageUrl:          c.ImagePublicUrl,
+	}
+
+	if c.Error != nil {
+		data.Error = c.Error.Error()
+	}
+
+	if ruleUrl, err := c.GetRuleUrl(); err == nil {
+		data.RuleUrl = ruleUrl
+	}
+
+	data.RuleName = c.render(c.Rule.Name, data)
+	if c.Group == nil {
+		data.Message = c.render(c.Rule.Message, data)
+		return data
+	}
+
+	lines := make([]string, 0, len(c.Group))
+	for _, member := range c.Group {
+		alert := member.GetTemplateData()
+		data.Alerts = append(data.Alerts, alert)
+
+		line := "[" + member.GetStateModel().Text + "] " + alert.RuleName
+		if alert.Message != "" {
+			line += ": " + alert.Message
+		}
+		lines = append(lines, line)
+	}
+	data.Message = strings.Join(lines, "\n")
+	return data
+}
+
+// GetRuleName returns the rule name rendered as a template.
+func (c *EvalContext) GetRuleName() string {
+	return c.GetTemplateData().RuleName
+}
+
+// GetMessage returns the rule message rendered as a template.
+func (c *EvalContext) GetMessage() string {
+	return c.GetTemplateData().Message
+}
+
+// RenderTemplate renders a template of a notification channel.
+func (c *EvalContext) RenderTemplate(text string) string {
+	return c.render(text, c.GetTemplateData())
+}
+
+// render falls back to the template text if it cannot be rendered, so a
+// notification is still sent.
+func (c *EvalContext) render(text string, data *TemplateData) string {
+	if text == "" {
+		return ""
+	}
+
+	result, err := renderTemplate(text, data)
+	if err != nil {
+		c.log.Error("Failed to render alert template", "alertId", c.Rule.Id, "error", err)
+		return text
+	}
+	return result
+}
+
+func handlePreviewTemplateCommand(cmd *PreviewTemplateCommand) error {
+	data := newSampleTemplateData()
+
+	if cmd.AlertId != 0 {
+		query := &m.GetAlertByIdQuery{Id: cmd.AlertId}
+		if err := bus.Dispatch(query); err != nil || query.Result.OrgId != cmd.OrgId {
+			return ValidationError{Reason: "Could not find alert to preview the template with"}
+		}
+
+		rule, err := NewRuleFromDBAlert(query.Result)
+		if err != nil {
+			return err
+		}
+
+		evalContext := NewEvalContext(context.Background(), rule)
+		evalContext.EvalMatches = evalMatchesFromData(query.Result.EvalData)
+		if executionError := strings.TrimSpace(query.Result.ExecutionError); executionError != "" {
+			evalContext.Error = errors.New(executionError)
+		}
+		data = evalContext.GetTemplateData()
+	}
+
+	result, err := renderTemplate(cmd.Template, data)
+	if err != nil {
+		return ValidationError{Err: err, Reason: "Could not render template"}
+	}
+
+	cmd.Result = result
+	return nil
+}
+
+// evalMatchesFromData reads the eval matches the alert saved with its last
+// state change.
+func evalMatchesFromData(evalData *simplejson.Json) []*EvalMatch {
+	if evalData == nil {
+		return make([]*EvalMatch, 0)
+	}
+	return parseEvalMatches(evalData.Get("evalMatches"))
+}
+
+// parseEvalMatches reads eval matches that were stored as JSON.
+func parseEvalMatches(data *simplejson.Json) []*EvalMatch {
+	matches := make([]*EvalMatch, 0)
+	for _, item := range data.MustArray() {
+		match := simplejson.NewFromAny(item)
+		evalMatch := &EvalMatch{
+			Metric: match.Get("metric").MustString(),
+			Tags:   make(map[string]string),
+		}
+		if value, err := match.Get("value").Float64(); err == nil {
+			evalMatch.Value = null.FloatFrom(value)
+		}
+		for key, value := range match.Get("tags").MustMap() {
+			if text, ok := value.(string); ok {
+				evalMatch.Tags[key] = text
+			}
+		}
+		matches = append(matches, evalMatch)
+	}
+	return matches
+}
@@ -0,0 +1,66 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/null"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertTemplates(t *testing.T) {
+	Convey("Test alert templates", t, func() {
+		ctx := NewEvalContext(context.TODO(), &Rule{
+			Id:      1,
+			Name:    "{{.State}} cpu",
+			Message: "{{range .EvalMatches}}{{.Tags.host}}={{.Value}} {{end}}was {{.PrevState}}",
+			State:   m.AlertStateAlerting,
+		})
+		ctx.PrevAlertState = m.AlertStateOK
+		ctx.EvalMatches = []*EvalMatch{
+			{Metric: "cpu", Value: null.FloatFrom(95), Tags: map[string]string{"host": "server1"}},
+		}
+
+		Convey("Renders the rule name and message", func() {
+			So(ctx.GetRuleName(), ShouldEqual, "alerting cpu")
+			So(ctx.GetMessage(), ShouldEqual, "server1=95.000 was ok")
+			So(ctx.GetNotificationTitle(), ShouldEqual, "[Alerting] alerting cpu")
+		})
+
+		Convey("Notification template can use the rendered message", func() {
+			ctx.Error = errors.New("timeout")
+			So(ctx.RenderTemplate("{{.RuleName}}: {{.Message}} {{.Error}}"), ShouldEqual, "alerting cpu: server1=95.000 was ok timeout")
+		})
+
+		Convey("Text without actions is unchanged", func() {
+			ctx.Rule.Message = "CPU is high"
+			So(ctx.GetMessage(), ShouldEqual, "CPU is high")
+		})
+
+		Convey("Template that cannot be rendered is sent as written", func() {
+			ctx.Rule.Message = "{{.Value}}"
+			So(ctx.GetMessage(), ShouldEqual, "{{.Value}}")
+		})
+
+		Convey("Text that is not a template is sent as written", func() {
+			ctx.Rule.Name = "cpu {{ high"
+			ctx.Rule.Message = "use {{ and }} to template"
+			So(ctx.GetRuleName(), ShouldEqual, "cpu {{ high")
+			So(ctx.GetMessage(), ShouldEqual, "use {{ and }} to template")
+		})
+
+		Convey("Validates syntax and fields", func() {
+			So(ValidateTemplate("{{.RuleName}} {{range .EvalMatches}}{{.Metric}}{{end}}"), ShouldBeNil)
+			So(ValidateTemplate("{{.RuleName"), ShouldNotBeNil)
+			So(ValidateTemplate("{{.Unknown}}"), ShouldNotBeNil)
+		})
+
+		Convey("Preview renders with sample data", func() {
+			cmd := &PreviewTemplateCommand{OrgId: 1, Template: "{{.RuleName}} {{len .EvalMatches}}"}
+			So(handlePreviewTemplateCommand(cmd), ShouldBeNil)
+			So(cmd.Result, ShouldEqual, "High CPU 1")
+		})
+	})
+}
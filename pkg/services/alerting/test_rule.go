@@ -22,28 +22,31 @@ func init() {
 }
 
 func handleAlertTestCommand(cmd *AlertTestCommand) error {
+	rule, err := getRuleFromDashboard(cmd.Dashboard, cmd.OrgId, cmd.PanelId)
+	if err != nil {
+		return err
+	}
 
-	dash := m.NewDashboardFromJson(cmd.Dashboard)
+	cmd.Result = testAlertRule(rule)
+	return nil
+}
+
+func getRuleFromDashboard(dashboard *simplejson.Json, orgId int64, panelId int64) (*Rule, error) {
+	dash := m.NewDashboardFromJson(dashboard)
 
-	extractor := NewDashAlertExtractor(dash, cmd.OrgId)
+	extractor := NewDashAlertExtractor(dash, orgId)
 	alerts, err := extractor.GetAlerts()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, alert := range alerts {
-		if alert.PanelId == cmd.PanelId {
-			rule, err := NewRuleFromDBAlert(alert)
-			if err != nil {
-				return err
-			}
-
-			cmd.Result = testAlertRule(rule)
-			return nil
+		if alert.PanelId == panelId {
+			return NewRuleFromDBAlert(alert)
 		}
 	}
 
-	return fmt.Errorf("Could not find alert with panel id %d", cmd.PanelId)
+	return nil, fmt.Errorf("Could not find alert with panel id %d", panelId)
 }
 
 func testAlertRule(rule *Rule) *EvalContext {
@@ -0,0 +1,91 @@
+package clustering
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// notifications are read again for this many seconds after they were created,
+// so one committed late or written by a node with a skewed clock is not missed
+const cacheInvalidationWindow = 60
+
+// cacheInvalidator evicts the items that any node in the cluster changed from
+// the caches of this node. The changes are recorded in the cluster_notification
+// table in the same transaction as the change itself.
+type cacheInvalidator struct {
+	since   int64
+	applied map[int64]int64 // notification id -> created
+	log     log.Logger
+}
+
+func newCacheInvalidator() *cacheInvalidator {
+	return &cacheInvalidator{
+		applied: make(map[int64]int64),
+		log:     log.New("clustering.cacheInvalidator"),
+	}
+}
+
+func (ci *cacheInvalidator) run(ctx context.Context) error {
+	ticker := time.NewTicker(time.Duration(setting.ClusteringInvalidationPoll) * time.Second)
+	defer ticker.Stop()
+
+	// a node that just started has nothing cached yet
+	ci.since = time.Now().Unix()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case tick := <-ticker.C:
+			if err := ci.poll(tick); err != nil {
+				ci.log.Error("Failed to read cluster notifications", "error", err)
+			}
+		}
+	}
+}
+
+// poll applies the notifications of the window before now that were not
+// applied yet.
+func (ci *cacheInvalidator) poll(now time.Time) error {
+	query := &m.GetClusterNotificationsQuery{Since: ci.since}
+	if err := bus.Dispatch(query); err != nil {
+		return err
+	}
+
+	refreshAlertRules := false
+	for _, notification := range query.Result {
+		if _, exists := ci.applied[notification.Id]; exists {
+			continue
+		}
+		ci.applied[notification.Id] = notification.Created
+		ci.log.Debug("Evicting cached item", "type", notification.Type, "orgId", notification.OrgId,
+			"itemId", notification.ItemId, "nodeId", notification.NodeId)
+		switch notification.Type {
+		case m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE:
+			m.EvictDataSourceTransport(notification.ItemId)
+		case m.CLUSTER_NOTIFICATION_TYPE_DASHBOARD, m.CLUSTER_NOTIFICATION_TYPE_ALERTS:
+			refreshAlertRules = true
+		}
+	}
+
+	if refreshAlertRules && setting.AlertingEnabled && setting.ExecuteAlerts {
+		if err := bus.Dispatch(&alerting.RefreshAlertRulesCommand{}); err != nil {
+			ci.log.Error("Failed to refresh alert rules", "error", err)
+		}
+	}
+
+	if since := now.Unix() - cacheInvalidationWindow; since > ci.since {
+		ci.since = since
+	}
+	for id, created := range ci.applied {
+		if created < ci.since {
+			delete(ci.applied, id)
+		}
+	}
+	return nil
+}
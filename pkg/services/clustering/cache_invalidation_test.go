@@ -0,0 +1,71 @@
+package clustering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCacheInvalidator(t *testing.T) {
+	Convey("Test cache invalidation from cluster notifications", t, func() {
+		setting.AlertingEnabled = true
+		setting.ExecuteAlerts = true
+
+		notifications := make([]*m.ClusterNotification, 0)
+		bus.AddHandler("test", func(query *m.GetClusterNotificationsQuery) error {
+			query.Result = make([]*m.ClusterNotification, 0)
+			for _, notification := range notifications {
+				if notification.Created >= query.Since {
+					query.Result = append(query.Result, notification)
+				}
+			}
+			return nil
+		})
+		refreshCount := 0
+		bus.AddHandler("test", func(cmd *alerting.RefreshAlertRulesCommand) error {
+			refreshCount++
+			return nil
+		})
+
+		now := time.Unix(1493233560, 0)
+		ci := newCacheInvalidator()
+		ci.since = now.Unix()
+
+		Convey("Alert rules are refreshed once for the changes of a poll", func() {
+			notifications = append(notifications,
+				&m.ClusterNotification{Id: 1, Type: m.CLUSTER_NOTIFICATION_TYPE_DASHBOARD, OrgId: 1, ItemId: 1, Created: now.Unix()},
+				&m.ClusterNotification{Id: 2, Type: m.CLUSTER_NOTIFICATION_TYPE_ALERTS, OrgId: 1, ItemId: 1, Created: now.Unix()})
+			So(ci.poll(now.Add(5*time.Second)), ShouldBeNil)
+			So(refreshCount, ShouldEqual, 1)
+
+			So(ci.poll(now.Add(10*time.Second)), ShouldBeNil)
+			So(refreshCount, ShouldEqual, 1)
+
+			Convey("Notification committed late is applied", func() {
+				notifications = append(notifications,
+					&m.ClusterNotification{Id: 3, Type: m.CLUSTER_NOTIFICATION_TYPE_ALERTS, OrgId: 1, ItemId: 2, Created: now.Unix() + 1})
+				So(ci.poll(now.Add(15*time.Second)), ShouldBeNil)
+				So(refreshCount, ShouldEqual, 2)
+			})
+
+			Convey("Applied notifications are forgotten after the window", func() {
+				So(ci.poll(now.Add((cacheInvalidationWindow+5)*time.Second)), ShouldBeNil)
+				So(len(ci.applied), ShouldEqual, 0)
+				So(refreshCount, ShouldEqual, 1)
+			})
+		})
+
+		Convey("Datasource changes do not refresh alert rules", func() {
+			notifications = append(notifications,
+				&m.ClusterNotification{Id: 1, Type: m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE, OrgId: 1, ItemId: 1, Created: now.Unix()})
+			So(ci.poll(now.Add(5*time.Second)), ShouldBeNil)
+			So(refreshCount, ShouldEqual, 0)
+			So(ci.applied[1], ShouldEqual, now.Unix())
+		})
+	})
+}
@@ -0,0 +1,59 @@
+package clustering
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/cron"
+	"github.com/grafana/grafana/pkg/log"
+	"github.com/grafana/grafana/pkg/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var clusterJobsLog = log.New("clustering.jobs")
+
+// clusterJob is a job the cluster manager runs on one node whenever its cron
+// schedule fires.
+type clusterJob struct {
+	name     string
+	schedule *cron.Schedule
+	nextRun  func() metrics.Gauge
+}
+
+func newClusterJobs() (cleanup, missingAlerts, hbRetention *clusterJob) {
+	cleanup = newClusterJob("cleanup", setting.ClusteringCleanupSchedule,
+		func() metrics.Gauge { return metrics.M_Clustering_Next_Cleanup })
+	missingAlerts = newClusterJob("missing alerts", setting.ClusteringMissingAlertsSchedule,
+		func() metrics.Gauge { return metrics.M_Clustering_Next_Missing_Alerts })
+	hbRetention = newClusterJob("heartbeat retention", setting.ClusteringHBRetentionSchedule,
+		func() metrics.Gauge { return metrics.M_Clustering_Next_HB_Retention })
+	return
+}
+
+func newClusterJob(name string, spec string, nextRun func() metrics.Gauge) *clusterJob {
+	job := &clusterJob{name: name, nextRun: nextRun}
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		// the schedules are validated when the settings are read
+		clusterJobsLog.Error("Invalid schedule, job disabled", "job", name, "error", err)
+		return job
+	}
+	job.schedule = schedule
+	return job
+}
+
+// isTime reports whether the job is due at the given tick. Jobs are due at the
+// 0th second of a matching minute.
+func (job *clusterJob) isTime(tick time.Time) bool {
+	return job.schedule != nil && tick.Second() == 0 && job.schedule.Matches(tick)
+}
+
+// planNextRun logs the next run of the job after t and publishes it as a metric.
+func (job *clusterJob) planNextRun(t time.Time) time.Time {
+	if job.schedule == nil {
+		return time.Time{}
+	}
+	next := job.schedule.Next(t)
+	job.nextRun().Update(next.Unix())
+	clusterJobsLog.Info("Next "+job.name+" run", "schedule", job.schedule.String(), "at", next)
+	return next
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -24,8 +25,19 @@ type ClusterManager struct {
 	alertingState        *AlertingState
 	dispatcherTaskQ      chan *DispatcherTask
 	dispatcherTaskStatus chan *DispatcherTaskStatus
+	drainLock            sync.Mutex
+	draining             bool
+	drained              chan struct{}
+	cleanupJob           *clusterJob
+	missingAlertsJob     *clusterJob
+	hbRetentionJob       *clusterJob
+	cacheInvalidator     *cacheInvalidator
 }
 
+var (
+	clusterManager *ClusterManager = nil
+)
+
 const (
 	DISPATCHER_TASK_TYPE_ALERTS_PARTITION = 0
 	DISPATCHER_TASK_TYPE_ALERTS_MISSING   = 1
@@ -36,19 +48,29 @@ type DispatcherTaskStatus struct {
 	taskType int
 	success  bool
 	errmsg   string
+	queuedAt time.Time
 }
 type DispatcherTask struct {
 	taskType int
 	taskInfo interface{}
+	queuedAt time.Time
 }
 
 type DispatcherTaskAlertsMissing struct {
+	nodeId        string
+	fencingToken  int64
 	missingAlerts []*m.Alert
 }
+type DispatcherTaskCleanup struct {
+	lastHeartbeat int64
+	heartbeats    bool
+	annotations   bool
+}
 type DispatcherTaskAlertsPartition struct {
-	partId    int
-	nodeCount int
-	interval  int64
+	nodeId   string
+	partId   int32
+	nodes    []string
+	interval int64
 }
 
 type AlertingState struct {
@@ -69,16 +91,27 @@ func NewClusterManager() *ClusterManager {
 		},
 		dispatcherTaskQ:      make(chan *DispatcherTask, 1),
 		dispatcherTaskStatus: make(chan *DispatcherTaskStatus, 1),
+		cacheInvalidator:     newCacheInvalidator(),
 	}
+	cm.cleanupJob, cm.missingAlertsJob, cm.hbRetentionJob = newClusterJobs()
+	clusterManager = cm
 	return cm
 }
 
 func (cm *ClusterManager) Run(parentCtx context.Context) error {
 	cm.log.Info("Initializing cluster manager")
 	var reterr error = nil
+	now := time.Now()
+	for _, job := range []*clusterJob{cm.cleanupJob, cm.missingAlertsJob, cm.hbRetentionJob} {
+		job.planNextRun(now)
+	}
 	taskGroup, ctx := errgroup.WithContext(parentCtx)
+	if backend, ok := cm.clusterNodeMgmt.(clusterNodeRunner); ok {
+		taskGroup.Go(func() error { return backend.Run(ctx) })
+	}
 	taskGroup.Go(func() error { return cm.clusterMgrTicker(ctx) })
 	taskGroup.Go(func() error { return cm.alertRulesDispatcher(ctx) })
+	taskGroup.Go(func() error { return cm.cacheInvalidator.run(ctx) })
 
 	if reterr := taskGroup.Wait(); reterr != nil {
 		msg := "Cluster manager stopped"
@@ -103,18 +136,27 @@ func (cm *ClusterManager) clusterMgrTicker(ctx context.Context) error {
 			return ctx.Err()
 		case x := <-cm.ticker.C: // ticks every second
 			if setting.AlertingEnabled && setting.ExecuteAlerts {
-				//execute cleanup scheduler everyday at 12.00.00 AM
-				if cm.isTimeForCleanup(x) {
+				if cm.isDraining() {
+					cm.drainTick(x)
+					continue
+				}
+				if cm.alertingState.status == m.CLN_ALERT_STATUS_DRAINING {
+					//draining was stopped, take part in alert scheduling again
+					cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_READY, m.CLN_ALERT_RUN_TYPE_NORMAL)
+					cm.checkin()
+				}
+				isTimeForCleanup, isTimeForHBRetention := cm.isTimeForCleanup(x), cm.isTimeForHBRetention(x)
+				if isTimeForCleanup || isTimeForHBRetention {
 					cm.log.Info("Time to run the cleanup scheduler on one node")
-					if cm.cleanupScheduler() {
+					if cm.cleanupScheduler(isTimeForHBRetention, isTimeForCleanup) {
 						cm.scheduleNormalAlerts()
 					}
-				} else if cm.isTimeToExecuteMissingAlerts(x) { //execute missing alerts after every 10 minutes
+				} else if cm.isTimeToExecuteMissingAlerts(x) {
 					cm.log.Debug("Time to run the missing alerts scheduler on one node")
 					if cm.scheduleMissingAlerts() {
 						cm.scheduleNormalAlerts()
 					}
-				} else if x.Second() == 0 { //schedule alert execution at the 0th second of every minute i.e every minute
+				} else if x.Second() == 0 { //assign alerts to nodes at the 0th second of every minute, the engine runs them at their frequency during the minute
 					cm.log.Debug("Time to run the normal alerts scheduler")
 					cm.scheduleNormalAlerts()
 				}
@@ -132,26 +174,29 @@ func (cm *ClusterManager) clusterMgrTicker(ctx context.Context) error {
 		}
 	}
 }
-func (cm *ClusterManager) isTimeForCleanup(time time.Time) bool {
-	currentHour := time.Hour()
-	interval := currentHour % setting.ClusteringCleanupPeriod
-	if interval == 0 && time.Minute() == 0 && time.Second() == 0 {
-		return true
-	}
-	return false
+func (cm *ClusterManager) isTimeForCleanup(tick time.Time) bool {
+	return cm.isTimeForJob(cm.cleanupJob, tick)
 }
 
-func (cm *ClusterManager) isTimeToExecuteMissingAlerts(time time.Time) bool {
-	currentMinute := time.Minute()
-	interval := currentMinute % setting.DefaultMissingAlertsSchedularTimeMinutes
-	if interval == 0 && time.Second() == 0 {
-		return true
+func (cm *ClusterManager) isTimeForHBRetention(tick time.Time) bool {
+	return cm.isTimeForJob(cm.hbRetentionJob, tick)
+}
+
+func (cm *ClusterManager) isTimeToExecuteMissingAlerts(tick time.Time) bool {
+	return cm.isTimeForJob(cm.missingAlertsJob, tick)
+}
+
+func (cm *ClusterManager) isTimeForJob(job *clusterJob, tick time.Time) bool {
+	if !job.isTime(tick) {
+		return false
 	}
-	return false
+	job.planNextRun(tick)
+	return true
 }
 
 func (cm *ClusterManager) checkin() {
 	if err := cm.clusterNodeMgmt.CheckIn(cm.alertingState, -1); err != nil {
+		metrics.M_Clustering_Rejected_CheckIns.Inc(1)
 		cm.log.Error("Failed to checkin", "error", err.Error())
 	}
 }
@@ -167,6 +212,9 @@ func (cm *ClusterManager) isAlertExecutionCompleted() bool {
 }
 
 func (cm *ClusterManager) handleDispatcherTaskStatus(taskStatus *DispatcherTaskStatus) {
+	if !taskStatus.queuedAt.IsZero() {
+		metrics.M_Clustering_Dispatcher_Latency.UpdateSince(taskStatus.queuedAt)
+	}
 	switch taskStatus.taskType {
 	case DISPATCHER_TASK_TYPE_ALERTS_PARTITION:
 		if taskStatus.success {
@@ -199,8 +247,9 @@ func (cm *ClusterManager) handleDispatcherTaskStatus(taskStatus *DispatcherTaskS
 	}
 }
 
-func (cm *ClusterManager) cleanupScheduler() bool {
-	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY {
+// cleanupScheduler runs the heartbeat and/or annotation cleanup on one node.
+func (cm *ClusterManager) cleanupScheduler(heartbeats bool, annotations bool) bool {
+	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY || cm.isDraining() {
 		return true
 	}
 	lastHeartbeat, err := cm.clusterNodeMgmt.GetLastHeartbeat()
@@ -210,6 +259,7 @@ func (cm *ClusterManager) cleanupScheduler() bool {
 	}
 	cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_SCHEDULING, m.CLN_ALERT_RUN_TYPE_CLEANUP)
 	if err := cm.clusterNodeMgmt.CheckIn(cm.alertingState, 1); err != nil {
+		metrics.M_Clustering_Rejected_CheckIns.Inc(1)
 		cm.log.Debug("Failed to checkin", "error", err.Error())
 		cm.log.Info("Other node is running cleanup job")
 		cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_READY, m.CLN_ALERT_RUN_TYPE_NORMAL)
@@ -218,9 +268,13 @@ func (cm *ClusterManager) cleanupScheduler() bool {
 	cm.log.Info("Scheduling cleanup")
 	dispatchTask := &DispatcherTask{
 		taskType: DISPATCHER_TASK_TYPE_CLEANUP,
-		taskInfo: lastHeartbeat,
+		taskInfo: &DispatcherTaskCleanup{
+			lastHeartbeat: lastHeartbeat,
+			heartbeats:    heartbeats,
+			annotations:   annotations,
+		},
 	}
-	cm.dispatcherTaskQ <- dispatchTask
+	cm.dispatch(dispatchTask)
 	return false
 }
 
@@ -236,17 +290,28 @@ func (cm *ClusterManager) hasPendingAlertJobs() bool {
 }
 
 func (cm *ClusterManager) scheduleMissingAlerts() bool {
-	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY {
+	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY || cm.isDraining() {
+		return true
+	}
+	// fetched before the claim, so a failure cannot leave it claimed
+	nodeID, err := cm.clusterNodeMgmt.GetNodeId()
+	if err != nil {
+		cm.log.Error("Failed to get node id", "error", err)
+		return true
+	}
+	lastHeartbeat, err := cm.clusterNodeMgmt.GetLastHeartbeat()
+	if err != nil {
+		cm.log.Error("Failed to get last heartbeat", "error", err)
 		return true
 	}
 	cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_SCHEDULING, m.CLN_ALERT_RUN_TYPE_MISSING)
-	err := cm.clusterNodeMgmt.CheckInNodeProcessingMissingAlerts(cm.alertingState)
+	err = cm.clusterNodeMgmt.CheckInNodeProcessingMissingAlerts(cm.alertingState)
 	if err != nil {
+		metrics.M_Clustering_Rejected_CheckIns.Inc(1)
 		cm.log.Info("Other node is picked to process missing alerts")
 		cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_READY, m.CLN_ALERT_RUN_TYPE_NORMAL)
 		return true
 	}
-	nodeID, err := cm.clusterNodeMgmt.GetNodeId()
 	cm.log.Info("Scheduling missing alerts", "nodeId", nodeID)
 	missingAlerts := cm.clusterNodeMgmt.GetMissingAlerts()
 	metrics.M_Clustering_Missing_Alerts_Count.Update(int64(len(missingAlerts)))
@@ -254,9 +319,9 @@ func (cm *ClusterManager) scheduleMissingAlerts() bool {
 	if missingAlerts != nil && len(missingAlerts) > 0 {
 		alertDispatchTask1 := &DispatcherTask{
 			taskType: DISPATCHER_TASK_TYPE_ALERTS_MISSING,
-			taskInfo: &DispatcherTaskAlertsMissing{missingAlerts: missingAlerts},
+			taskInfo: &DispatcherTaskAlertsMissing{nodeId: nodeID, fencingToken: lastHeartbeat, missingAlerts: missingAlerts},
 		}
-		cm.dispatcherTaskQ <- alertDispatchTask1
+		cm.dispatch(alertDispatchTask1)
 		return false
 	} else {
 		cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_READY, m.CLN_ALERT_RUN_TYPE_NORMAL)
@@ -266,7 +331,7 @@ func (cm *ClusterManager) scheduleMissingAlerts() bool {
 
 func (cm *ClusterManager) scheduleNormalAlerts() {
 	cm.log.Info("Scheduling normal alerts")
-	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY {
+	if cm.alertingState.status != m.CLN_ALERT_STATUS_READY || cm.isDraining() {
 		return
 	}
 	lastHeartbeat, err := cm.clusterNodeMgmt.GetLastHeartbeat()
@@ -279,19 +344,20 @@ func (cm *ClusterManager) scheduleNormalAlerts() {
 		AlertStatus:  cm.alertingState.status,
 		AlertRunType: cm.alertingState.run_type,
 	}
-	//get node deatils for last heartbeat which contains partitionId.
+	//only nodes that checked in as ready for last heartbeat take part in alert scheduling.
 	activeNode, err := cm.clusterNodeMgmt.GetNode(node)
 	if err != nil {
 		cm.log.Warn("Failed to get node for heartbeat "+strconv.FormatInt(lastHeartbeat, 10), "error", err)
 		cm.checkin()
 		return
 	}
-	//Get total node count to distribute alerts among nodes
-	nodeCount, err := cm.clusterNodeMgmt.GetActiveNodesCount(lastHeartbeat)
+	//Get all active nodes to build the hash ring that distributes alerts among nodes
+	nodes, err := cm.clusterNodeMgmt.GetActiveNodes(lastHeartbeat)
 	if err != nil {
-		cm.log.Error("Failed to get active node count for heartbeat "+string(lastHeartbeat), "error", err)
+		cm.log.Error("Failed to get active nodes for heartbeat "+strconv.FormatInt(lastHeartbeat, 10), "error", err)
 		return
 	}
+	nodeCount := len(nodes)
 	metrics.M_Clustering_Active_Nodes.Update(int64(nodeCount))
 	cm.log.Debug(fmt.Sprintf("Total active nodes as %v", nodeCount))
 	if nodeCount == 0 {
@@ -302,12 +368,20 @@ func (cm *ClusterManager) scheduleNormalAlerts() {
 	alertDispatchTask := &DispatcherTask{
 		taskType: DISPATCHER_TASK_TYPE_ALERTS_PARTITION,
 		taskInfo: &DispatcherTaskAlertsPartition{
-			interval:  lastHeartbeat,
-			nodeCount: nodeCount,
-			partId:    int(activeNode.PartId),
+			interval: lastHeartbeat,
+			nodeId:   activeNode.NodeId,
+			partId:   activeNode.PartId,
+			nodes:    nodes,
 		},
 	}
-	cm.dispatcherTaskQ <- alertDispatchTask
+	cm.dispatch(alertDispatchTask)
+}
+
+// dispatch queues the task for the dispatcher. The queue time is carried to
+// the task status to measure the dispatcher latency.
+func (cm *ClusterManager) dispatch(task *DispatcherTask) {
+	task.queuedAt = time.Now()
+	cm.dispatcherTaskQ <- task
 }
 
 func (cm *ClusterManager) alertRulesDispatcher(ctx context.Context) error {
@@ -334,38 +408,53 @@ func (cm *ClusterManager) handleDispatcherTask(task *DispatcherTask) {
 	case DISPATCHER_TASK_TYPE_ALERTS_PARTITION:
 		taskInfo := task.taskInfo.(*DispatcherTaskAlertsPartition)
 		scheduleCmd := &alerting.ScheduleAlertsForPartitionCommand{
-			Interval:  taskInfo.interval,
-			NodeCount: taskInfo.nodeCount,
-			PartId:    taskInfo.partId,
+			Interval: taskInfo.interval,
+			NodeId:   taskInfo.nodeId,
+			PartId:   taskInfo.partId,
+			Nodes:    taskInfo.nodes,
 		}
 		cm.log.Info("Dispatcher - submitted normal alerts batch")
 		err = bus.Dispatch(scheduleCmd)
 	case DISPATCHER_TASK_TYPE_ALERTS_MISSING:
 		taskInfo := task.taskInfo.(*DispatcherTaskAlertsMissing)
 		scheduleCmd := &alerting.ScheduleMissingAlertsCommand{
+			NodeId:        taskInfo.nodeId,
+			FencingToken:  taskInfo.fencingToken,
 			MissingAlerts: taskInfo.missingAlerts,
 		}
 		err = bus.Dispatch(scheduleCmd)
 		cm.log.Info("Dispatcher - submitted missing alerts batch")
 	case DISPATCHER_TASK_TYPE_CLEANUP:
-		ts := task.taskInfo.(int64)
+		taskInfo := task.taskInfo.(*DispatcherTaskCleanup)
 		cm.changeAlertingState(m.CLN_ALERT_STATUS_PROCESSING)
-		cm.log.Info("Dispatcher - running cleanup job")
-		cmd := &m.ClusteringCleanupCommand{LastHeartbeat: ts}
+		cm.log.Info("Dispatcher - running cleanup job", "heartbeats", taskInfo.heartbeats, "annotations", taskInfo.annotations)
+		cmd := &m.ClusteringCleanupCommand{
+			LastHeartbeat:   taskInfo.lastHeartbeat,
+			FencingToken:    taskInfo.lastHeartbeat,
+			SkipHeartbeats:  !taskInfo.heartbeats,
+			SkipAnnotations: !taskInfo.annotations,
+		}
 		err = bus.Dispatch(cmd)
 	default:
 		err = errors.New("Invalid task type " + string(task.taskType))
 		cm.log.Error(err.Error())
 	}
+	if err == m.ErrStaleFencingToken {
+		metrics.M_Clustering_Stale_Tasks.Inc(1)
+		cm.log.Warn("Dispatcher - task refused, its heartbeat is stale", "taskType", task.taskType)
+	}
 	if err != nil {
-		cm.dispatcherTaskStatus <- &DispatcherTaskStatus{task.taskType, false, err.Error()}
+		cm.dispatcherTaskStatus <- &DispatcherTaskStatus{task.taskType, false, err.Error(), task.queuedAt}
 	} else {
-		cm.dispatcherTaskStatus <- &DispatcherTaskStatus{task.taskType, true, ""}
+		cm.dispatcherTaskStatus <- &DispatcherTaskStatus{task.taskType, true, "", task.queuedAt}
 	}
 }
 
 func (cm *ClusterManager) changeAlertingState(newState string) {
 	cm.log.Info("Alerting state: " + cm.alertingState.status + " -> " + newState)
+	if cm.alertingState.status != newState {
+		metrics.M_Clustering_State_Transitions.With(cm.alertingState.status, newState).Inc(1)
+	}
 	cm.alertingState.status = newState
 }
 
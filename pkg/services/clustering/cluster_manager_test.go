@@ -60,7 +60,7 @@ func TestClusterManager(t *testing.T) {
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
 			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 1)
 			So(mockCNM.callCountGetNode, ShouldEqual, 1)
-			So(mockCNM.callCountGetActiveNodesCount, ShouldEqual, 1)
+			So(mockCNM.callCountGetActiveNodes, ShouldEqual, 1)
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
 			So(len(cm.dispatcherTaskQ), ShouldEqual, 1)
 
@@ -72,6 +72,7 @@ func TestClusterManager(t *testing.T) {
 			status := <-cm.dispatcherTaskStatus
 			So(status.success, ShouldBeTrue)
 			So(status.taskType, ShouldEqual, DISPATCHER_TASK_TYPE_ALERTS_PARTITION)
+			So(status.queuedAt.IsZero(), ShouldBeFalse)
 			cm.handleDispatcherTaskStatus(status)
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_PROCESSING)
 
@@ -89,7 +90,7 @@ func TestClusterManager(t *testing.T) {
 			cm.scheduleNormalAlerts()
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
 			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 1)
-			So(mockCNM.callCountGetActiveNodesCount, ShouldEqual, 1)
+			So(mockCNM.callCountGetActiveNodes, ShouldEqual, 1)
 			So(mockCNM.callCountGetNode, ShouldEqual, 1)
 			So(len(cm.dispatcherTaskQ), ShouldEqual, 1)
 
@@ -150,6 +151,7 @@ func TestClusterManagerForMissingAlerts(t *testing.T) {
 			// missing alerts dispatch successful
 			handlers.scheduleMissingAlertsErr = nil
 			missingAlertTask := <-cm.dispatcherTaskQ
+			So(missingAlertTask.taskInfo.(*DispatcherTaskAlertsMissing).fencingToken, ShouldEqual, 1493233440)
 			cm.handleDispatcherTask(missingAlertTask)
 			missingAlertTaskStatus := <-cm.dispatcherTaskStatus
 			So(missingAlertTaskStatus.success, ShouldBeTrue)
@@ -179,7 +181,7 @@ func TestClusterManagerForMissingAlerts(t *testing.T) {
 			mockCNM := &mockClusterNodeMgmt{
 				nodeId: "testnode:3000",
 			}
-			mockCNM.retError = errors.New("Other node is picked to process missing alerts")
+			mockCNM.checkInMissingAlertsError = errors.New("Other node is picked to process missing alerts")
 			cm.clusterNodeMgmt = mockCNM
 			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
 			otherNodeProcessingMissingAlerts := cm.scheduleMissingAlerts()
@@ -204,6 +206,22 @@ func TestClusterManagerForMissingAlerts(t *testing.T) {
 			So(cm.alertingState.run_type, ShouldEqual, m.CLN_ALERT_RUN_TYPE_NORMAL)
 			So(missingAlertsNotfound, ShouldBeTrue)
 		})
+
+		Convey("Test Missing alerts are not scheduled when the node id is unknown", func() {
+			handlers.reset()
+			mockCNM := &mockClusterNodeMgmt{
+				nodeIdError: errors.New("Cluster node object is nil"),
+			}
+			cm.clusterNodeMgmt = mockCNM
+			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
+			So(cm.scheduleMissingAlerts(), ShouldBeTrue)
+			So(mockCNM.callCountGetNodeId, ShouldEqual, 1)
+			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 0)
+			So(mockCNM.callCountCheckInNodeProcessingMissingAlerts, ShouldEqual, 0)
+			So(mockCNM.callCountGetMissingAlerts, ShouldEqual, 0)
+			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_READY)
+			So(cm.alertingState.run_type, ShouldEqual, m.CLN_ALERT_RUN_TYPE_NORMAL)
+		})
 	})
 }
 
@@ -231,7 +249,7 @@ func TestClusterManagerForCleanupScheduler(t *testing.T) {
 			}
 			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
 			cm.clusterNodeMgmt = mockCNM
-			cm.cleanupScheduler()
+			cm.cleanupScheduler(true, true)
 			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 1)
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
 			So(cm.alertingState.run_type, ShouldEqual, m.CLN_ALERT_RUN_TYPE_CLEANUP)
@@ -244,16 +262,38 @@ func TestClusterManagerForCleanupScheduler(t *testing.T) {
 			cleanupTaskStatus := <-cm.dispatcherTaskStatus
 			So(cleanupTaskStatus.taskType, ShouldEqual, DISPATCHER_TASK_TYPE_CLEANUP)
 			So(cleanupTaskStatus.success, ShouldBeTrue)
+			So(handlers.cleanupCmd.FencingToken, ShouldEqual, 1493233440)
 			cm.handleDispatcherTaskStatus(cleanupTaskStatus)
 			//normal alerts should be scheduled after cleanup
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
 			So(cm.alertingState.run_type, ShouldEqual, m.CLN_ALERT_RUN_TYPE_NORMAL)
 
 			//clean up not scheduled if node not in ready state
-			notInReadyState := cm.cleanupScheduler()
+			notInReadyState := cm.cleanupScheduler(true, true)
 			So(notInReadyState, ShouldBeTrue)
 		})
 
+		Convey("Test Cleanup from a stale heartbeat is refused", func() {
+			handlers.reset()
+			mockCNM := &mockClusterNodeMgmt{
+				nodeId:        "testnode:3000",
+				lastHeartbeat: 1493233440,
+			}
+			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
+			cm.clusterNodeMgmt = mockCNM
+			cm.cleanupScheduler(true, true)
+
+			cleanupTask := <-cm.dispatcherTaskQ
+			handlers.cleanupSchedulerErr = m.ErrStaleFencingToken
+			cm.handleDispatcherTask(cleanupTask)
+			cleanupTaskStatus := <-cm.dispatcherTaskStatus
+			So(cleanupTaskStatus.success, ShouldBeFalse)
+			So(cleanupTaskStatus.errmsg, ShouldEqual, m.ErrStaleFencingToken.Error())
+			cm.handleDispatcherTaskStatus(cleanupTaskStatus)
+			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_READY)
+			So(cm.alertingState.run_type, ShouldEqual, m.CLN_ALERT_RUN_TYPE_NORMAL)
+		})
+
 		Convey("Test Cleanup not scheduled if other node is processing cleanup", func() {
 			handlers.reset()
 			mockCNM := &mockClusterNodeMgmt{
@@ -263,7 +303,7 @@ func TestClusterManagerForCleanupScheduler(t *testing.T) {
 			mockCNM.checkInError = errors.New("Failed to checkin. Other node is running cleanup job")
 			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
 			cm.clusterNodeMgmt = mockCNM
-			isOtherNodeDoingCleanup := cm.cleanupScheduler()
+			isOtherNodeDoingCleanup := cm.cleanupScheduler(true, true)
 			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 1)
 			So(mockCNM.callCountCheckIn, ShouldEqual, 1)
 			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_READY)
@@ -273,22 +313,162 @@ func TestClusterManagerForCleanupScheduler(t *testing.T) {
 	})
 }
 
+func TestClusterManagerJobSchedules(t *testing.T) {
+	Convey("Validate cluster manager job schedules", t, func() {
+		setting.NewConfigContext(&setting.CommandLineArgs{
+			HomePath: "../../../",
+		})
+		setting.AlertingEnabled = true
+		setting.ExecuteAlerts = true
+		setting.ClusteringEnabled = true
+		setting.ClusteringCleanupSchedule = "30 2 * * *"
+		setting.ClusteringMissingAlertsSchedule = "*/5 * * * *"
+		setting.ClusteringHBRetentionSchedule = "0 */6 * * *"
+
+		handlers := &mockHandlers{}
+		bus.AddHandler("test", handlers.ClusteringCleanupCommand)
+		cm := NewClusterManager()
+		at := func(value string) time.Time {
+			t, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+			So(err, ShouldBeNil)
+			return t
+		}
+
+		Convey("Test jobs run when their schedule fires", func() {
+			So(cm.isTimeForCleanup(at("2017-04-26 02:30:00")), ShouldBeTrue)
+			So(cm.isTimeForCleanup(at("2017-04-26 02:30:01")), ShouldBeFalse)
+			So(cm.isTimeForCleanup(at("2017-04-26 00:00:00")), ShouldBeFalse)
+			So(cm.isTimeToExecuteMissingAlerts(at("2017-04-26 10:25:00")), ShouldBeTrue)
+			So(cm.isTimeToExecuteMissingAlerts(at("2017-04-26 10:21:00")), ShouldBeFalse)
+			So(cm.isTimeForHBRetention(at("2017-04-26 12:00:00")), ShouldBeTrue)
+			So(cm.isTimeForHBRetention(at("2017-04-26 13:00:00")), ShouldBeFalse)
+		})
+
+		Convey("Test next run is planned", func() {
+			So(cm.cleanupJob.planNextRun(at("2017-04-26 02:30:00")), ShouldResemble, at("2017-04-27 02:30:00"))
+			So(cm.missingAlertsJob.planNextRun(at("2017-04-26 10:25:00")), ShouldResemble, at("2017-04-26 10:30:00"))
+			So(cm.hbRetentionJob.planNextRun(at("2017-04-26 12:00:00")), ShouldResemble, at("2017-04-26 18:00:00"))
+		})
+
+		Convey("Test heartbeat retention runs without annotation cleanup", func() {
+			handlers.reset()
+			mockCNM := &mockClusterNodeMgmt{nodeId: "testnode:3000", lastHeartbeat: 1493233440}
+			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
+			cm.clusterNodeMgmt = mockCNM
+			So(cm.cleanupScheduler(true, false), ShouldBeFalse)
+			cm.handleDispatcherTask(<-cm.dispatcherTaskQ)
+			So((<-cm.dispatcherTaskStatus).success, ShouldBeTrue)
+			So(handlers.cleanupCmd.LastHeartbeat, ShouldEqual, 1493233440)
+			So(handlers.cleanupCmd.SkipHeartbeats, ShouldBeFalse)
+			So(handlers.cleanupCmd.SkipAnnotations, ShouldBeTrue)
+		})
+	})
+}
+
+func TestClusterManagerDrain(t *testing.T) {
+	Convey("Validate cluster manager node drain", t, func() {
+		setting.NewConfigContext(&setting.CommandLineArgs{
+			HomePath: "../../../",
+		})
+		setting.AlertingEnabled = true
+		setting.ExecuteAlerts = true
+		setting.ClusteringEnabled = true
+
+		handlers := &mockHandlers{}
+		bus.AddHandler("test", handlers.getPendingJobCount)
+		cm := NewClusterManager()
+
+		Convey("Test drain waits for scheduling to complete", func() {
+			handlers.reset()
+			mockCNM := &mockClusterNodeMgmt{nodeId: "testnode:3000"}
+			cm.clusterNodeMgmt = mockCNM
+			cm.alertingState.status = m.CLN_ALERT_STATUS_SCHEDULING
+			drained := cm.Drain(true)
+			cm.drainTick(time.Unix(1493233501, 0))
+			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_SCHEDULING)
+			So(mockCNM.callCountCheckIn, ShouldEqual, 0)
+
+			// scheduling done, jobs still running
+			cm.alertingState.status = m.CLN_ALERT_STATUS_PROCESSING
+			handlers.pendingJobCount = 0
+			cm.drainTick(time.Unix(1493233502, 0))
+			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_DRAINING)
+			So(mockCNM.callCountCheckIn, ShouldEqual, 1)
+			So(isClosed(drained), ShouldBeTrue)
+		})
+
+		Convey("Test draining node keeps checking in and schedules nothing", func() {
+			handlers.reset()
+			mockCNM := &mockClusterNodeMgmt{
+				nodeId:          "testnode:3000",
+				activeNodeCount: 1,
+				lastHeartbeat:   1493233500,
+				activeNode:      &m.ActiveNode{PartId: 0, AlertStatus: m.CLN_ALERT_STATUS_READY},
+			}
+			cm.clusterNodeMgmt = mockCNM
+			cm.alertingState.status = m.CLN_ALERT_STATUS_READY
+			handlers.pendingJobCount = 1
+			drained := cm.Drain(true)
+			cm.drainTick(time.Unix(1493233501, 0))
+			So(cm.alertingState.status, ShouldEqual, m.CLN_ALERT_STATUS_DRAINING)
+			So(isClosed(drained), ShouldBeFalse)
+
+			cm.drainTick(time.Unix(1493233502, 0))
+			So(mockCNM.callCountCheckIn, ShouldEqual, 1)
+			cm.drainTick(time.Unix(1493233560, 0))
+			So(mockCNM.callCountCheckIn, ShouldEqual, 2)
+
+			cm.scheduleNormalAlerts()
+			So(cm.scheduleMissingAlerts(), ShouldBeTrue)
+			So(cm.cleanupScheduler(true, true), ShouldBeTrue)
+			So(mockCNM.callCountGetLastHeartbeat, ShouldEqual, 0)
+			So(len(cm.dispatcherTaskQ), ShouldEqual, 0)
+
+			handlers.pendingJobCount = 0
+			handlers.scheduledUntil = 1493233620
+			cm.drainTick(time.Unix(1493233561, 0))
+			So(isClosed(drained), ShouldBeFalse)
+
+			cm.drainTick(time.Unix(1493233620, 0))
+			So(isClosed(drained), ShouldBeTrue)
+
+			cm.Drain(false)
+			So(cm.isDraining(), ShouldBeFalse)
+		})
+	})
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
 type mockHandlers struct {
 	pendingJobCount               int
+	scheduledUntil                int64
 	alerts                        []*m.Alert
 	scheduleAlertsForPartitionErr error
 	scheduleMissingAlertsErr      error
 	cleanupSchedulerErr           error
+	cleanupCmd                    *m.ClusteringCleanupCommand
 }
 
 func (mh *mockHandlers) reset() {
 	mh.pendingJobCount = 0
+	mh.scheduledUntil = 0
+	mh.cleanupCmd = nil
 	mh.alerts = nil
 	mh.scheduleAlertsForPartitionErr = nil
 	mh.scheduleMissingAlertsErr = nil
+	mh.cleanupSchedulerErr = nil
 }
 func (mh *mockHandlers) getPendingJobCount(query *alerting.PendingAlertJobCountQuery) error {
 	query.ResultCount = mh.pendingJobCount
+	query.ResultScheduledUntil = mh.scheduledUntil
 	return nil
 }
 func (mh *mockHandlers) getMissingAlertsQuery(query *m.GetMissingAlertsQuery) error {
@@ -304,6 +484,7 @@ func (mh *mockHandlers) ScheduleMissingAlertsCommand(cmd *alerting.ScheduleMissi
 }
 
 func (mh *mockHandlers) ClusteringCleanupCommand(cmd *m.ClusteringCleanupCommand) error {
+	mh.cleanupCmd = cmd
 	return mh.cleanupSchedulerErr
 }
 
@@ -320,14 +501,20 @@ type mockClusterNodeMgmt struct {
 	callCountGetNode                            int
 	callCountCheckInNodeProcessingMissingAlerts int
 	callCountGetActiveNodesCount                int
+	callCountGetActiveNodes                     int
 	callCountGetLastHeartbeat                   int
 	callCountGetMissingAlerts                   int
 	callCountGetNodeProcessingMissingAlerts     int
 	checkInError                                error
+	checkInMissingAlertsError                   error
+	nodeIdError                                 error
 }
 
 func (cn *mockClusterNodeMgmt) GetNodeId() (string, error) {
 	cn.callCountGetNodeId++
+	if cn.nodeIdError != nil {
+		return "", cn.nodeIdError
+	}
 	return cn.nodeId, cn.retError
 }
 func (cn *mockClusterNodeMgmt) CheckIn(alertingState *AlertingState, participantLimit int) error {
@@ -340,12 +527,20 @@ func (cn *mockClusterNodeMgmt) GetNode(node *m.ActiveNode) (*m.ActiveNode, error
 }
 func (cn *mockClusterNodeMgmt) CheckInNodeProcessingMissingAlerts(alertingState *AlertingState) error {
 	cn.callCountCheckInNodeProcessingMissingAlerts++
-	return cn.retError
+	return cn.checkInMissingAlertsError
 }
 func (cn *mockClusterNodeMgmt) GetActiveNodesCount(heartbeat int64) (int, error) {
 	cn.callCountGetActiveNodesCount++
 	return cn.activeNodeCount, cn.retError
 }
+func (cn *mockClusterNodeMgmt) GetActiveNodes(heartbeat int64) ([]string, error) {
+	cn.callCountGetActiveNodes++
+	nodes := make([]string, 0)
+	for i := 0; i < cn.activeNodeCount; i++ {
+		nodes = append(nodes, fmt.Sprintf("%v-%v", cn.nodeId, i))
+	}
+	return nodes, cn.retError
+}
 func (cn *mockClusterNodeMgmt) GetLastHeartbeat() (int64, error) {
 	cn.callCountGetLastHeartbeat++
 	return cn.lastHeartbeat, cn.retError
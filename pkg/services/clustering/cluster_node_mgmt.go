@@ -1,6 +1,7 @@
 package clustering
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -10,17 +11,27 @@ import (
 	"github.com/grafana/grafana/pkg/setting"
 )
 
+// ClusterNodeMgmt is the cluster membership backend. ClusterNode keeps the
+// membership in the active_node table, GossipClusterNode gossips it between
+// the nodes. The backend is picked with the [clustering] backend setting.
 type ClusterNodeMgmt interface {
 	GetNodeId() (string, error)
 	CheckIn(alertingState *AlertingState, participantLimit int) error
 	GetNode(node *m.ActiveNode) (*m.ActiveNode, error)
 	CheckInNodeProcessingMissingAlerts(alertingState *AlertingState) error
 	GetActiveNodesCount(heartbeat int64) (int, error)
+	GetActiveNodes(heartbeat int64) ([]string, error)
 	GetLastHeartbeat() (int64, error)
 	GetMissingAlerts() []*m.Alert
 	GetNodeProcessingMissingAlerts() *m.ActiveNode
 }
 
+// clusterNodeRunner is implemented by backends that need background work,
+// the cluster manager runs them along with its own routines.
+type clusterNodeRunner interface {
+	Run(ctx context.Context) error
+}
+
 type ClusterNode struct {
 	nodeId string
 	log    log.Logger
@@ -41,12 +52,16 @@ func getClusterNode() ClusterNodeMgmt {
 	if clusterNodeMgmt != nil {
 		return clusterNodeMgmt
 	}
-	node := &ClusterNode{
-		nodeId: getCurrentNodeId(),
-		log:    log.New("clustering.clusterNode"),
+	switch setting.ClusteringBackend {
+	case "gossip":
+		clusterNodeMgmt = newGossipClusterNodeFromSettings()
+	default:
+		clusterNodeMgmt = &ClusterNode{
+			nodeId: getCurrentNodeId(),
+			log:    log.New("clustering.clusterNode"),
+		}
 	}
-	clusterNodeMgmt = node
-	return node
+	return clusterNodeMgmt
 
 }
 
@@ -136,6 +151,31 @@ func (node *ClusterNode) GetActiveNodesCount(heartbeat int64) (int, error) {
 	return cmd.Result, nil
 }
 
+func (node *ClusterNode) GetActiveNodes(heartbeat int64) ([]string, error) {
+	if node == nil {
+		return nil, errors.New("Cluster node object is nil")
+	}
+	cmd := &m.GetLatestActiveNodesQuery{Heartbeat: heartbeat}
+	if err := bus.Dispatch(cmd); err != nil {
+		node.log.Error(fmt.Sprintf("Failed to get active nodes for heartbeat %v", heartbeat), "error", err)
+		return nil, err
+	}
+	nodes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, activeNode := range cmd.Result {
+		if activeNode.AlertStatus != m.CLN_ALERT_STATUS_READY || seen[activeNode.NodeId] {
+			continue
+		}
+		seen[activeNode.NodeId] = true
+		nodes = append(nodes, activeNode.NodeId)
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New(fmt.Sprintf("No active nodes found for heartbeat %v", heartbeat))
+	}
+	node.log.Debug("GetLatestActiveNodesQuery executed successfully", "nodes", nodes)
+	return nodes, nil
+}
+
 func (node *ClusterNode) GetLastHeartbeat() (int64, error) {
 	if node == nil {
 		return 0, errors.New("Cluster node object is nil")
@@ -0,0 +1,642 @@
+package clustering
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/hashring"
+	"github.com/grafana/grafana/pkg/log"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	gossipMaxPacketSize      = 65000
+	gossipFanout             = 3
+	gossipRetainedHeartbeats = 10
+)
+
+// GossipClusterNode keeps cluster membership in memory and spreads it between
+// the nodes with a gossip protocol instead of writing it to the active_node table.
+// Every node records its own check-ins per heartbeat (the start of the minute on
+// the database clock, which the fencing tokens are checked against) and gossips
+// the recent ones over UDP to a few random peers.
+// A full state push/pull over TCP with one random peer repairs anything lost.
+// Every message is signed with the shared gossip_secret, unsigned messages and
+// messages of nodes with another secret are dropped.
+type GossipClusterNode struct {
+	nodeId        string
+	bindAddr      string
+	advertiseAddr string
+	secret        []byte
+	interval      time.Duration
+	log           log.Logger
+	now           func() time.Time      // bounds how long states are kept
+	dbHeartbeat   func() (int64, error) // start of the current minute on the database clock
+
+	lock   sync.RWMutex
+	peers  map[string]bool
+	states map[gossipStateKey]*gossipNodeState
+
+	udpConn     *net.UDPConn
+	tcpListener net.Listener
+}
+
+type gossipStateKey struct {
+	nodeId       string
+	heartbeat    int64
+	alertRunType string
+}
+
+type gossipNodeState struct {
+	NodeId       string `json:"nodeId"`
+	Heartbeat    int64  `json:"heartbeat"`
+	Version      int64  `json:"version"`
+	AlertRunType string `json:"alertRunType"`
+	AlertStatus  string `json:"alertStatus"`
+}
+
+type gossipMessage struct {
+	From   string             `json:"from"`
+	Addr   string             `json:"addr"`
+	States []*gossipNodeState `json:"states"`
+}
+
+// gossipEnvelope carries an encoded gossip message with its HMAC-SHA256.
+type gossipEnvelope struct {
+	Message json.RawMessage `json:"message"`
+	Mac     []byte          `json:"mac"`
+}
+
+func newGossipClusterNode(nodeId string, bindAddr string, advertiseAddr string, secret string, peers []string, interval time.Duration) *GossipClusterNode {
+	node := &GossipClusterNode{
+		nodeId:        nodeId,
+		bindAddr:      bindAddr,
+		advertiseAddr: advertiseAddr,
+		secret:        []byte(secret),
+		interval:      interval,
+		log:           log.New("clustering.gossipNode"),
+		now:           time.Now,
+		dbHeartbeat:   currentDBHeartbeat,
+		peers:         make(map[string]bool),
+		states:        make(map[gossipStateKey]*gossipNodeState),
+	}
+	node.addPeers(peers...)
+	return node
+}
+
+func newGossipClusterNodeFromSettings() *GossipClusterNode {
+	section := setting.Cfg.Section("clustering")
+	// only reachable from this host unless an address is configured
+	bindAddr := section.Key("gossip_bind_address").MustString("127.0.0.1:7946")
+	advertiseAddr := section.Key("gossip_advertise_address").MustString(bindAddr)
+	peers := make([]string, 0)
+	for _, peer := range section.Key("gossip_peers").Strings(",") {
+		if peer != "" {
+			peers = append(peers, peer)
+		}
+	}
+	interval := time.Duration(section.Key("gossip_interval_ms").MustInt(1000)) * time.Millisecond
+	return newGossipClusterNode(getCurrentNodeId(), bindAddr, advertiseAddr, setting.ClusteringGossipSecret, peers, interval)
+}
+
+func (node *GossipClusterNode) mac(data []byte) []byte {
+	hash := hmac.New(sha256.New, node.secret)
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+func (node *GossipClusterNode) seal(msg *gossipMessage) (*gossipEnvelope, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &gossipEnvelope{Message: data, Mac: node.mac(data)}, nil
+}
+
+func (node *GossipClusterNode) open(envelope *gossipEnvelope) (*gossipMessage, error) {
+	if !hmac.Equal(envelope.Mac, node.mac(envelope.Message)) {
+		return nil, errors.New("Invalid gossip message signature")
+	}
+	msg := &gossipMessage{}
+	if err := json.Unmarshal(envelope.Message, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Listen binds the UDP and TCP listeners. It is called by Run, tests call it
+// directly to learn the address of a node bound to port 0.
+func (node *GossipClusterNode) Listen() error {
+	if node.udpConn != nil {
+		return nil
+	}
+	tcpListener, err := net.Listen("tcp", node.bindAddr)
+	if err != nil {
+		return err
+	}
+	udpAddr, err := net.ResolveUDPAddr("udp", tcpListener.Addr().String())
+	if err != nil {
+		tcpListener.Close()
+		return err
+	}
+	udpConn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		tcpListener.Close()
+		return err
+	}
+	node.tcpListener = tcpListener
+	node.udpConn = udpConn
+
+	host, _, _ := net.SplitHostPort(node.advertiseAddr)
+	_, port, _ := net.SplitHostPort(tcpListener.Addr().String())
+	node.advertiseAddr = net.JoinHostPort(host, port)
+	node.log.Info("Gossip listening", "address", tcpListener.Addr().String(), "advertise", node.advertiseAddr)
+	return nil
+}
+
+func (node *GossipClusterNode) Addr() string {
+	return node.advertiseAddr
+}
+
+// Join adds peers to gossip with.
+func (node *GossipClusterNode) Join(peers ...string) {
+	node.addPeers(peers...)
+	for _, peer := range peers {
+		if err := node.pushPull(peer); err != nil {
+			node.log.Debug("Failed to join peer", "peer", peer, "error", err)
+		}
+	}
+}
+
+func (node *GossipClusterNode) Run(ctx context.Context) error {
+	if err := node.Listen(); err != nil {
+		node.log.Error("Failed to start gossip listener", "error", err)
+		return err
+	}
+	go node.serveUDP()
+	go node.serveTCP()
+
+	gossipTicker := time.NewTicker(node.interval)
+	pushPullTicker := time.NewTicker(node.interval * 10)
+	defer gossipTicker.Stop()
+	defer pushPullTicker.Stop()
+
+	node.pushPullRandomPeer()
+	for {
+		select {
+		case <-ctx.Done():
+			node.udpConn.Close()
+			node.tcpListener.Close()
+			return ctx.Err()
+		case <-gossipTicker.C:
+			node.gossip()
+		case <-pushPullTicker.C:
+			node.prune()
+			node.pushPullRandomPeer()
+		}
+	}
+}
+
+func (node *GossipClusterNode) addPeers(peers ...string) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	for _, peer := range peers {
+		if peer != "" && peer != node.advertiseAddr {
+			node.peers[peer] = true
+		}
+	}
+}
+
+func (node *GossipClusterNode) randomPeers(count int) []string {
+	node.lock.RLock()
+	peers := make([]string, 0, len(node.peers))
+	for peer := range node.peers {
+		peers = append(peers, peer)
+	}
+	node.lock.RUnlock()
+
+	for i := range peers {
+		j := rand.Intn(i + 1)
+		peers[i], peers[j] = peers[j], peers[i]
+	}
+	if len(peers) > count {
+		peers = peers[:count]
+	}
+	return peers
+}
+
+// currentDBHeartbeat returns the start of the current minute on the database
+// clock, so the heartbeats of all nodes agree whatever their own clocks say.
+func currentDBHeartbeat() (int64, error) {
+	query := &m.GetLastDBTimeIntervalQuery{}
+	if err := bus.Dispatch(query); err != nil {
+		return 0, err
+	}
+	return query.Result + 60, nil
+}
+
+// currentHeartbeat is the start of the current minute on the node's clock. It
+// only bounds the states that are gossiped and kept.
+func (node *GossipClusterNode) currentHeartbeat() int64 {
+	return node.now().Unix() / 60 * 60
+}
+
+func (node *GossipClusterNode) message(sinceHeartbeat int64) *gossipMessage {
+	node.lock.RLock()
+	defer node.lock.RUnlock()
+	msg := &gossipMessage{From: node.nodeId, Addr: node.advertiseAddr, States: make([]*gossipNodeState, 0)}
+	for _, state := range node.states {
+		if state.Heartbeat >= sinceHeartbeat {
+			msg.States = append(msg.States, state)
+		}
+	}
+	return msg
+}
+
+func (node *GossipClusterNode) merge(msg *gossipMessage, remote net.Addr) {
+	if msg.Addr != "" && remote != nil {
+		host, port, err := net.SplitHostPort(msg.Addr)
+		if err == nil && (host == "" || net.ParseIP(host) != nil && net.ParseIP(host).IsUnspecified()) {
+			if remoteHost, _, err := net.SplitHostPort(remote.String()); err == nil {
+				msg.Addr = net.JoinHostPort(remoteHost, port)
+			}
+		}
+		node.addPeers(msg.Addr)
+	}
+
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	oldest := node.currentHeartbeat() - gossipRetainedHeartbeats*60
+	for _, state := range msg.States {
+		// only the node itself changes its own state
+		if state.NodeId == node.nodeId || state.Heartbeat < oldest {
+			continue
+		}
+		key := gossipStateKey{state.NodeId, state.Heartbeat, state.AlertRunType}
+		if existing, ok := node.states[key]; !ok || existing.Version < state.Version {
+			node.states[key] = state
+		}
+	}
+}
+
+func (node *GossipClusterNode) prune() {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	oldest := node.currentHeartbeat() - gossipRetainedHeartbeats*60
+	for key := range node.states {
+		if key.heartbeat < oldest {
+			delete(node.states, key)
+		}
+	}
+}
+
+func (node *GossipClusterNode) gossip() {
+	envelope, err := node.seal(node.message(node.currentHeartbeat() - 60))
+	if err != nil {
+		node.log.Error("Failed to encode gossip message", "error", err)
+		return
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		node.log.Error("Failed to encode gossip message", "error", err)
+		return
+	}
+	if len(data) > gossipMaxPacketSize {
+		node.log.Warn("Gossip message too large for UDP, relying on TCP push/pull", "size", len(data))
+		return
+	}
+	for _, peer := range node.randomPeers(gossipFanout) {
+		addr, err := net.ResolveUDPAddr("udp", peer)
+		if err != nil {
+			node.log.Debug("Failed to resolve peer", "peer", peer, "error", err)
+			continue
+		}
+		if _, err := node.udpConn.WriteToUDP(data, addr); err != nil {
+			node.log.Debug("Failed to gossip to peer", "peer", peer, "error", err)
+		}
+	}
+}
+
+func (node *GossipClusterNode) serveUDP() {
+	buf := make([]byte, gossipMaxPacketSize)
+	for {
+		n, remote, err := node.udpConn.ReadFromUDP(buf)
+		if err != nil {
+			node.log.Debug("Gossip UDP listener stopped", "error", err)
+			return
+		}
+		envelope := &gossipEnvelope{}
+		if err := json.Unmarshal(buf[:n], envelope); err != nil {
+			node.log.Debug("Invalid gossip message", "remote", remote, "error", err)
+			continue
+		}
+		msg, err := node.open(envelope)
+		if err != nil {
+			node.log.Warn("Dropped gossip message", "remote", remote, "error", err)
+			continue
+		}
+		node.merge(msg, remote)
+	}
+}
+
+func (node *GossipClusterNode) serveTCP() {
+	for {
+		conn, err := node.tcpListener.Accept()
+		if err != nil {
+			node.log.Debug("Gossip TCP listener stopped", "error", err)
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(node.interval * 5))
+			envelope := &gossipEnvelope{}
+			if err := json.NewDecoder(conn).Decode(envelope); err != nil {
+				node.log.Debug("Invalid push/pull message", "remote", conn.RemoteAddr(), "error", err)
+				return
+			}
+			msg, err := node.open(envelope)
+			if err != nil {
+				node.log.Warn("Dropped push/pull message", "remote", conn.RemoteAddr(), "error", err)
+				return
+			}
+			if err := node.sendPushPull(conn); err != nil {
+				node.log.Debug("Failed to answer push/pull", "remote", conn.RemoteAddr(), "error", err)
+			}
+			node.merge(msg, conn.RemoteAddr())
+		}()
+	}
+}
+
+func (node *GossipClusterNode) pushPullRandomPeer() {
+	for _, peer := range node.randomPeers(1) {
+		if err := node.pushPull(peer); err != nil {
+			node.log.Debug("Push/pull failed", "peer", peer, "error", err)
+		}
+	}
+}
+
+func (node *GossipClusterNode) pushPull(peer string) error {
+	conn, err := net.DialTimeout("tcp", peer, node.interval*5)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(node.interval * 5))
+	if err := node.sendPushPull(conn); err != nil {
+		return err
+	}
+	envelope := &gossipEnvelope{}
+	if err := json.NewDecoder(conn).Decode(envelope); err != nil {
+		return err
+	}
+	msg, err := node.open(envelope)
+	if err != nil {
+		return err
+	}
+	node.merge(msg, conn.RemoteAddr())
+	return nil
+}
+
+// sendPushPull writes the full state of the node to a push/pull connection.
+func (node *GossipClusterNode) sendPushPull(conn net.Conn) error {
+	envelope, err := node.seal(node.message(0))
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(conn).Encode(envelope)
+}
+
+func (node *GossipClusterNode) record(alertingState *AlertingState) error {
+	heartbeat, err := node.dbHeartbeat()
+	if err != nil {
+		return err
+	}
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	key := gossipStateKey{node.nodeId, heartbeat, alertingState.run_type}
+	var version int64 = 1
+	if existing, ok := node.states[key]; ok {
+		version = existing.Version + 1
+	}
+	node.states[key] = &gossipNodeState{
+		NodeId:       node.nodeId,
+		Heartbeat:    heartbeat,
+		Version:      version,
+		AlertRunType: alertingState.run_type,
+		AlertStatus:  alertingState.status,
+	}
+	return nil
+}
+
+func (node *GossipClusterNode) statesFor(heartbeat int64) []*gossipNodeState {
+	node.lock.RLock()
+	defer node.lock.RUnlock()
+	result := make([]*gossipNodeState, 0)
+	for key, state := range node.states {
+		if key.heartbeat == heartbeat {
+			result = append(result, state)
+		}
+	}
+	sort.Sort(byNodeId(result))
+	return result
+}
+
+// elect picks one node among the nodes active at the last heartbeats to run a
+// job of the given type. Every node computes the same owner once the last
+// heartbeats have been gossiped, which replaces the insert race on active_node.
+// Without any state of the last heartbeats a node does not know its peers, so
+// no node is elected and the job is skipped until the states have been gossiped.
+func (node *GossipClusterNode) elect(runType string) (string, error) {
+	heartbeat, err := node.dbHeartbeat()
+	if err != nil {
+		return "", err
+	}
+	lastHeartbeat := heartbeat - 60
+	nodes, err := node.recentActiveNodes(lastHeartbeat)
+	if err != nil {
+		return "", err
+	}
+	ring := hashring.New(setting.ClusteringVirtualNodes, nodes...)
+	return ring.Get(runType + ":" + strconv.FormatInt(lastHeartbeat, 10)), nil
+}
+
+// recentActiveNodes returns the nodes that were ready at their latest check-in
+// of the given heartbeat or the one before it. A node that was busy at the
+// start of the minute, or whose check-in reached this node late, may have no
+// state for the given heartbeat and still takes part in the election.
+func (node *GossipClusterNode) recentActiveNodes(heartbeat int64) ([]string, error) {
+	latest := make(map[string]int64)
+	ready := make(map[string]bool)
+	for _, hb := range []int64{heartbeat - 60, heartbeat} {
+		for _, state := range node.statesFor(hb) {
+			if latest[state.NodeId] != hb {
+				latest[state.NodeId] = hb
+				ready[state.NodeId] = false
+			}
+			if state.AlertStatus == m.CLN_ALERT_STATUS_READY {
+				ready[state.NodeId] = true
+			}
+		}
+	}
+	nodes := make([]string, 0)
+	for nodeId, isReady := range ready {
+		if isReady {
+			nodes = append(nodes, nodeId)
+		}
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New(fmt.Sprintf("No active nodes found for heartbeats %v and %v", heartbeat-60, heartbeat))
+	}
+	sort.Strings(nodes)
+	return nodes, nil
+}
+
+func (node *GossipClusterNode) GetNodeId() (string, error) {
+	if node == nil {
+		return "", errors.New("Cluster node object is nil")
+	}
+	return node.nodeId, nil
+}
+
+func (node *GossipClusterNode) CheckIn(alertingState *AlertingState, participantLimit int) error {
+	if node == nil {
+		return errors.New("Cluster node object is nil")
+	}
+	if participantLimit > 0 {
+		owner, err := node.elect(alertingState.run_type)
+		if err != nil {
+			return err
+		}
+		if owner != node.nodeId {
+			return errors.New("Participant limit reached")
+		}
+	}
+	return node.record(alertingState)
+}
+
+func (node *GossipClusterNode) GetNode(currentNode *m.ActiveNode) (*m.ActiveNode, error) {
+	if node == nil {
+		return nil, errors.New("Cluster node object is nil")
+	}
+	partId := 0
+	for _, state := range node.statesFor(currentNode.Heartbeat) {
+		if state.AlertStatus != currentNode.AlertStatus || state.AlertRunType != currentNode.AlertRunType {
+			continue
+		}
+		if state.NodeId == node.nodeId {
+			return &m.ActiveNode{
+				NodeId:       state.NodeId,
+				Heartbeat:    state.Heartbeat,
+				PartId:       int32(partId),
+				AlertRunType: state.AlertRunType,
+				AlertStatus:  state.AlertStatus,
+			}, nil
+		}
+		partId++
+	}
+	return nil, errors.New(fmt.Sprintf("Heartbeat record not found: nodeId=%s, heartbeat=%d, status=%s, runType=%s",
+		node.nodeId, currentNode.Heartbeat, currentNode.AlertStatus, currentNode.AlertRunType))
+}
+
+func (node *GossipClusterNode) CheckInNodeProcessingMissingAlerts(alertingState *AlertingState) error {
+	if node == nil {
+		return errors.New("Cluster node object is nil")
+	}
+	owner, err := node.elect(m.CLN_ALERT_RUN_TYPE_MISSING)
+	if err != nil {
+		return err
+	}
+	if owner != node.nodeId {
+		return errors.New("Other node is processing missing alerts: " + owner)
+	}
+	return node.record(alertingState)
+}
+
+func (node *GossipClusterNode) GetActiveNodesCount(heartbeat int64) (int, error) {
+	nodes, err := node.GetActiveNodes(heartbeat)
+	if err != nil {
+		return 0, err
+	}
+	return len(nodes), nil
+}
+
+func (node *GossipClusterNode) GetActiveNodes(heartbeat int64) ([]string, error) {
+	if node == nil {
+		return nil, errors.New("Cluster node object is nil")
+	}
+	nodes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, state := range node.statesFor(heartbeat) {
+		if state.AlertStatus != m.CLN_ALERT_STATUS_READY || seen[state.NodeId] {
+			continue
+		}
+		seen[state.NodeId] = true
+		nodes = append(nodes, state.NodeId)
+	}
+	if len(nodes) == 0 {
+		return nil, errors.New(fmt.Sprintf("No active nodes found for heartbeat %v", heartbeat))
+	}
+	return nodes, nil
+}
+
+func (node *GossipClusterNode) GetLastHeartbeat() (int64, error) {
+	if node == nil {
+		return 0, errors.New("Cluster node object is nil")
+	}
+	heartbeat, err := node.dbHeartbeat()
+	if err != nil {
+		node.log.Error("Failed to get db time interval", "error", err)
+		return 0, err
+	}
+	return heartbeat - 60, nil
+}
+
+func (node *GossipClusterNode) GetMissingAlerts() []*m.Alert {
+	missingAlertsQuery := &m.GetMissingAlertsQuery{}
+	if err := bus.Dispatch(missingAlertsQuery); err != nil {
+		node.log.Error("GetMissingAlertsQuery failed to execute", "error", err)
+	}
+	return missingAlertsQuery.Result
+}
+
+func (node *GossipClusterNode) GetNodeProcessingMissingAlerts() *m.ActiveNode {
+	heartbeat, err := node.dbHeartbeat()
+	if err != nil {
+		node.log.Error("Failed to get db time interval", "error", err)
+		return nil
+	}
+	for _, state := range node.statesFor(heartbeat) {
+		if state.AlertRunType == m.CLN_ALERT_RUN_TYPE_MISSING {
+			return &m.ActiveNode{
+				NodeId:       state.NodeId,
+				Heartbeat:    state.Heartbeat,
+				AlertRunType: state.AlertRunType,
+				AlertStatus:  state.AlertStatus,
+			}
+		}
+	}
+	return nil
+}
+
+type byNodeId []*gossipNodeState
+
+func (s byNodeId) Len() int { return len(s) }
+func (s byNodeId) Less(i, j int) bool {
+	if s[i].NodeId == s[j].NodeId {
+		return s[i].AlertRunType < s[j].AlertRunType
+	}
+	return s[i].NodeId < s[j].NodeId
+}
+func (s byNodeId) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,183 @@
+package clustering
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func startGossipNodes(ctx context.Context, count int, clock *testClock) ([]*GossipClusterNode, error) {
+	nodes := make([]*GossipClusterNode, 0, count)
+	for i := 0; i < count; i++ {
+		node := newGossipClusterNode(fmt.Sprintf("node%d:3000", i), "127.0.0.1:0", "127.0.0.1:0", "secret", nil, 20*time.Millisecond)
+		node.now = clock.Now
+		node.dbHeartbeat = func() (int64, error) {
+			return clock.Now().Unix() / 60 * 60, nil
+		}
+		if err := node.Listen(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	for _, node := range nodes {
+		for _, peer := range nodes {
+			if peer != node {
+				node.addPeers(peer.Addr())
+			}
+		}
+		go node.Run(ctx)
+	}
+	return nodes, nil
+}
+
+func waitForActiveNodes(node *GossipClusterNode, heartbeat int64, count int) int {
+	var nodes []string
+	for i := 0; i < 250; i++ {
+		nodes, _ = node.GetActiveNodes(heartbeat)
+		if len(nodes) == count {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return len(nodes)
+}
+
+func TestGossipClusterNode(t *testing.T) {
+	Convey("Given three gossip nodes on localhost", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		clock := &testClock{now: time.Unix(1493233500, 0)}
+		nodes, err := startGossipNodes(ctx, 3, clock)
+		So(err, ShouldBeNil)
+
+		ready := &AlertingState{status: m.CLN_ALERT_STATUS_READY, run_type: m.CLN_ALERT_RUN_TYPE_NORMAL}
+		for _, node := range nodes {
+			So(node.CheckIn(ready, -1), ShouldBeNil)
+		}
+
+		Convey("Every node learns about every check-in", func() {
+			for _, node := range nodes {
+				So(waitForActiveNodes(node, 1493233500, 3), ShouldEqual, 3)
+			}
+
+			activeNode, err := nodes[1].GetNode(&m.ActiveNode{
+				Heartbeat:    1493233500,
+				AlertStatus:  m.CLN_ALERT_STATUS_READY,
+				AlertRunType: m.CLN_ALERT_RUN_TYPE_NORMAL,
+			})
+			So(err, ShouldBeNil)
+			So(activeNode.NodeId, ShouldEqual, "node1:3000")
+			So(activeNode.PartId, ShouldEqual, 1)
+		})
+
+		Convey("Exactly one node is elected for cleanup and missing alerts", func() {
+			for _, node := range nodes {
+				So(waitForActiveNodes(node, 1493233500, 3), ShouldEqual, 3)
+			}
+			clock.Add(time.Minute)
+
+			lastHeartbeat, err := nodes[0].GetLastHeartbeat()
+			So(err, ShouldBeNil)
+			So(lastHeartbeat, ShouldEqual, 1493233500)
+
+			cleanup := &AlertingState{status: m.CLN_ALERT_STATUS_SCHEDULING, run_type: m.CLN_ALERT_RUN_TYPE_CLEANUP}
+			missing := &AlertingState{status: m.CLN_ALERT_STATUS_SCHEDULING, run_type: m.CLN_ALERT_RUN_TYPE_MISSING}
+			cleanupOwners := 0
+			missingOwners := 0
+			for _, node := range nodes {
+				if node.CheckIn(cleanup, 1) == nil {
+					cleanupOwners++
+				}
+				if node.CheckInNodeProcessingMissingAlerts(missing) == nil {
+					missingOwners++
+				}
+			}
+			So(cleanupOwners, ShouldEqual, 1)
+			So(missingOwners, ShouldEqual, 1)
+		})
+
+		Convey("A node that missed the last heartbeat still takes part in the election", func() {
+			for _, node := range nodes {
+				So(waitForActiveNodes(node, 1493233500, 3), ShouldEqual, 3)
+			}
+			clock.Add(time.Minute)
+			So(nodes[0].CheckIn(ready, -1), ShouldBeNil)
+			So(nodes[1].CheckIn(ready, -1), ShouldBeNil)
+			for _, node := range nodes {
+				So(waitForActiveNodes(node, 1493233560, 2), ShouldEqual, 2)
+			}
+			clock.Add(time.Minute)
+
+			cleanup := &AlertingState{status: m.CLN_ALERT_STATUS_SCHEDULING, run_type: m.CLN_ALERT_RUN_TYPE_CLEANUP}
+			cleanupOwners := 0
+			for _, node := range nodes {
+				active, err := node.recentActiveNodes(1493233560)
+				So(err, ShouldBeNil)
+				So(len(active), ShouldEqual, 3)
+				if node.CheckIn(cleanup, 1) == nil {
+					cleanupOwners++
+				}
+			}
+			So(cleanupOwners, ShouldEqual, 1)
+		})
+
+		Convey("No node is elected without the state of the last heartbeats", func() {
+			clock.Add(3 * time.Minute)
+
+			cleanup := &AlertingState{status: m.CLN_ALERT_STATUS_SCHEDULING, run_type: m.CLN_ALERT_RUN_TYPE_CLEANUP}
+			missing := &AlertingState{status: m.CLN_ALERT_STATUS_SCHEDULING, run_type: m.CLN_ALERT_RUN_TYPE_MISSING}
+			for _, node := range nodes {
+				So(node.CheckIn(cleanup, 1), ShouldNotBeNil)
+				So(node.CheckInNodeProcessingMissingAlerts(missing), ShouldNotBeNil)
+			}
+		})
+
+		Convey("Messages of a node with another secret are dropped", func() {
+			for _, node := range nodes {
+				So(waitForActiveNodes(node, 1493233500, 3), ShouldEqual, 3)
+			}
+			outsider := newGossipClusterNode("outsider:3000", "127.0.0.1:0", "127.0.0.1:0", "other", nil, 20*time.Millisecond)
+			outsider.now = clock.Now
+			outsider.dbHeartbeat = nodes[0].dbHeartbeat
+			So(outsider.Listen(), ShouldBeNil)
+			defer outsider.udpConn.Close()
+			defer outsider.tcpListener.Close()
+			So(outsider.CheckIn(ready, -1), ShouldBeNil)
+
+			So(outsider.pushPull(nodes[0].Addr()), ShouldNotBeNil)
+			active, err := nodes[0].GetActiveNodes(1493233500)
+			So(err, ShouldBeNil)
+			So(len(active), ShouldEqual, 3)
+		})
+
+		Convey("Draining node is left out of the active nodes", func() {
+			draining := &AlertingState{status: m.CLN_ALERT_STATUS_DRAINING, run_type: m.CLN_ALERT_RUN_TYPE_NORMAL}
+			So(nodes[2].CheckIn(draining, -1), ShouldBeNil)
+			So(waitForActiveNodes(nodes[0], 1493233500, 2), ShouldEqual, 2)
+			So(waitForActiveNodes(nodes[1], 1493233500, 2), ShouldEqual, 2)
+		})
+	})
+}
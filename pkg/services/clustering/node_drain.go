@@ -0,0 +1,112 @@
+package clustering
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+)
+
+var ErrDrainTimeout = errors.New("Timed out waiting for alert jobs to finish")
+
+// DrainNodeCommand takes this node out of alert scheduling (Drain = true) or
+// puts it back (Drain = false). With a Timeout the command waits until the
+// in-flight alert jobs are done.
+type DrainNodeCommand struct {
+	Drain   bool
+	Timeout time.Duration
+
+	ResultNodeId string
+}
+
+func init() {
+	bus.AddHandler("clustering", drainNode)
+}
+
+func drainNode(cmd *DrainNodeCommand) error {
+	if clusterManager == nil {
+		return errors.New("Cluster manager is not initialized")
+	}
+	nodeId, err := clusterManager.clusterNodeMgmt.GetNodeId()
+	if err != nil {
+		return err
+	}
+	cmd.ResultNodeId = nodeId
+
+	drained := clusterManager.Drain(cmd.Drain)
+	if !cmd.Drain || cmd.Timeout <= 0 {
+		return nil
+	}
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(cmd.Timeout):
+		return ErrDrainTimeout
+	}
+}
+
+// Drain starts or stops draining. The returned channel is closed once the
+// node has checked in as draining and has no alert jobs left, including the
+// ones still due in the interval it was scheduled for.
+func (cm *ClusterManager) Drain(drain bool) <-chan struct{} {
+	cm.drainLock.Lock()
+	defer cm.drainLock.Unlock()
+	if drain && !cm.draining {
+		cm.log.Info("Draining node")
+		cm.draining = true
+		cm.drained = make(chan struct{})
+	} else if !drain && cm.draining {
+		cm.log.Info("Stopped draining node")
+		cm.draining = false
+	}
+	return cm.drained
+}
+
+func (cm *ClusterManager) isDraining() bool {
+	cm.drainLock.Lock()
+	defer cm.drainLock.Unlock()
+	return cm.draining
+}
+
+func (cm *ClusterManager) markDrained() {
+	cm.drainLock.Lock()
+	defer cm.drainLock.Unlock()
+	select {
+	case <-cm.drained:
+	default:
+		cm.log.Info("Node drained")
+		close(cm.drained)
+	}
+}
+
+// hasScheduledAlertJobs reports whether the alert engine is still running the
+// rules of the interval it was last given.
+func (cm *ClusterManager) hasScheduledAlertJobs(tick time.Time) bool {
+	query := &alerting.PendingAlertJobCountQuery{}
+	if err := bus.Dispatch(query); err != nil {
+		cm.log.Error("Failed to get scheduled alert jobs", "error", err)
+		return true
+	}
+	return query.ResultScheduledUntil > tick.Unix()
+}
+
+// drainTick runs on every tick while draining. It waits for the current
+// scheduling to complete, checks in as draining so that the other nodes take
+// over this node's alerts on their next tick, and keeps checking in as
+// draining every heartbeat until draining stops.
+func (cm *ClusterManager) drainTick(tick time.Time) {
+	if cm.alertingState.status != m.CLN_ALERT_STATUS_DRAINING {
+		if !cm.isAlertExecutionCompleted() {
+			return
+		}
+		cm.changeAlertingStateAndRunType(m.CLN_ALERT_STATUS_DRAINING, m.CLN_ALERT_RUN_TYPE_NORMAL)
+		cm.checkin()
+	} else if tick.Second() == 0 {
+		cm.checkin()
+	}
+	if !cm.hasPendingAlertJobs() && !cm.hasScheduledAlertJobs(tick) {
+		cm.markDrained()
+	}
+}
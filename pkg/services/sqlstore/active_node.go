@@ -12,10 +12,13 @@ import (
 
 var (
 	insertHeartbeatSQL  = "insert into active_node(node_id, heartbeat, part_id, alert_run_type, alert_status) values(?, ?, ?, ?, ?)"
-	getNextPartIDSQL    = "select count(part_id) as part_id from active_node where heartbeat = ? and alert_status = ?"
+	getNextPartIDSQL    = "select coalesce(max(part_id) + 1, 0) as part_id from active_node where heartbeat = ? and alert_status = ?"
+	deleteNodeHBSQL     = "delete from active_node where node_id = ? and heartbeat = ? and alert_run_type = ?"
 	lastCleanupCheckSQL = "select * from active_node as a where a.heartbeat > ? and a.heartbeat <= ? and alert_run_type='" + m.CLN_ALERT_RUN_TYPE_CLEANUP + "'"
 	deleteHearbeatSQL   = "delete from active_node where heartbeat < ?"
 	deleteAnnotationSQL = "delete from annotation where epoch < ?"
+	lastHeartbeatsSQL   = "select distinct heartbeat from active_node order by heartbeat desc"
+	lastCheckInSQL      = "select node_id, max(heartbeat) as heartbeat from active_node group by node_id"
 )
 
 func init() {
@@ -27,6 +30,11 @@ func init() {
 	bus.AddHandler("sql", GetActiveNodesCount)
 	bus.AddHandler("sql", GetNodeProcessingMissingAlerts)
 	bus.AddHandler("sql", ClusteringCleanup)
+	bus.AddHandler("sql", GetLatestActiveNodes)
+	bus.AddHandler("sql", GetActiveNodeHistory)
+	bus.AddHandler("sql", GetActiveNodesLastCheckIn)
+	bus.AddHandler("sql", GetLastNodeForRunType)
+	bus.AddHandler("sql", ValidateFencingToken)
 }
 
 func GetActiveNodeByIdHeartbeat(query *m.GetActiveNodeByIdHeartbeatQuery) error {
@@ -97,6 +105,14 @@ func InsertActiveNodeHeartbeat(cmd *m.SaveActiveNodeCommand) error {
 				sqlog.Debug(errmsg, "error", err)
 				return errors.New(errmsg + ": " + err.Error())
 			}
+			if cmd.Node.AlertStatus == m.CLN_ALERT_STATUS_DRAINING {
+				// a draining node replaces its check-in for this heartbeat so it is left out of the next partitioning
+				if _, err = sess.Exec(deleteNodeHBSQL, cmd.Node.NodeId, ts, cmd.Node.AlertRunType); err != nil {
+					errmsg := "Failed to replace heartbeat"
+					sqlog.Debug(errmsg, "error", err)
+					return errors.New(errmsg + ": " + err.Error())
+				}
+			}
 			results, err = sess.Query(getNextPartIDSQL, ts, cmd.Node.AlertStatus)
 			if err != nil {
 				errmsg := "Failed to get next part_id"
@@ -190,6 +206,33 @@ func GetLastDBTimeInterval(cmd *m.GetLastDBTimeIntervalQuery) error {
 	return nil
 }
 
+func ValidateFencingToken(query *m.ValidateFencingTokenQuery) error {
+	sess := newSession()
+	defer sess.Close()
+	return validateFencingToken(sess, query.Token)
+}
+
+// validateFencingToken checks the token against the database clock, so a node
+// that was paused after it scheduled work cannot run it once the heartbeat
+// interval has passed.
+func validateFencingToken(sess *DBSession, token int64) error {
+	results, err := sess.Query("select " + dialect.CurrentTimeToRoundMinSql() + " as ts ")
+	if err != nil {
+		sqlog.Error("Failed to get db timestamp", "error", err)
+		return err
+	}
+	ts, err := strconv.ParseInt(string(results[0]["ts"]), 10, 64)
+	if err != nil {
+		sqlog.Error("Failed to get db timestamp", "error", err)
+		return err
+	}
+	if token < ts-60 {
+		sqlog.Warn("Refusing work for stale heartbeat", "fencingToken", token, "heartbeat", ts-60)
+		return m.ErrStaleFencingToken
+	}
+	return nil
+}
+
 func validAlertRunType(status string) bool {
 	switch status {
 	case m.CLN_ALERT_RUN_TYPE_MISSING:
@@ -207,6 +250,7 @@ func validAlertStatus(status string) bool {
 	case m.CLN_ALERT_STATUS_READY:
 	case m.CLN_ALERT_STATUS_PROCESSING:
 	case m.CLN_ALERT_STATUS_SCHEDULING:
+	case m.CLN_ALERT_STATUS_DRAINING:
 	default:
 		return false
 	}
@@ -248,6 +292,89 @@ func GetNodeProcessingMissingAlerts(cmd *m.GetNodeProcessingMissingAlertsCommand
 	return nil
 }
 
+func GetLatestActiveNodes(query *m.GetLatestActiveNodesQuery) error {
+	if query.Heartbeat == 0 {
+		lastHeartbeat := m.GetLastDBTimeIntervalQuery{}
+		if err := GetLastDBTimeInterval(&lastHeartbeat); err != nil {
+			return err
+		}
+		query.Heartbeat = lastHeartbeat.Result
+	}
+	nodes := make([]*m.ActiveNode, 0)
+	err := x.Where("heartbeat=?", query.Heartbeat).Asc("part_id").Asc("node_id").Find(&nodes)
+	if err != nil {
+		sqlog.Error(fmt.Sprintf("Failed to get active nodes for heartbeat=%d", query.Heartbeat), "error", err)
+		return err
+	}
+	query.Result = nodes
+	return nil
+}
+
+func GetActiveNodeHistory(query *m.GetActiveNodeHistoryQuery) error {
+	if query.Heartbeats <= 0 {
+		query.Heartbeats = 10
+	}
+	results, err := x.Query(fmt.Sprintf("%s LIMIT %d", lastHeartbeatsSQL, query.Heartbeats))
+	if err != nil {
+		sqlog.Error("Failed to get last heartbeats", "error", err)
+		return err
+	}
+	nodes := make([]*m.ActiveNode, 0)
+	if len(results) == 0 {
+		query.Result = nodes
+		return nil
+	}
+	oldest, err := strconv.ParseInt(string(results[len(results)-1]["heartbeat"]), 10, 64)
+	if err != nil {
+		sqlog.Error("Failed to get last heartbeats", "error", err)
+		return err
+	}
+	sess := x.Where("heartbeat>=?", oldest)
+	if query.NodeId != "" {
+		sess.And("node_id=?", query.NodeId)
+	}
+	if err := sess.Desc("heartbeat").Asc("node_id").Find(&nodes); err != nil {
+		sqlog.Error("Failed to get active node history", "nodeId", query.NodeId, "error", err)
+		return err
+	}
+	query.Result = nodes
+	return nil
+}
+
+func GetActiveNodesLastCheckIn(query *m.GetActiveNodesLastCheckInQuery) error {
+	results, err := x.Query(lastCheckInSQL)
+	if err != nil {
+		sqlog.Error("Failed to get last node check-in", "error", err)
+		return err
+	}
+	query.Result = make(map[string]int64)
+	for _, row := range results {
+		heartbeat, err := strconv.ParseInt(string(row["heartbeat"]), 10, 64)
+		if err != nil {
+			sqlog.Error("Failed to get last node check-in", "error", err)
+			return err
+		}
+		query.Result[string(row["node_id"])] = heartbeat
+	}
+	return nil
+}
+
+func GetLastNodeForRunType(query *m.GetLastNodeForRunTypeQuery) error {
+	if !validAlertRunType(query.AlertRunType) {
+		return errors.New("Invalid alert run type " + query.AlertRunType)
+	}
+	var retNode m.ActiveNode
+	has, err := x.Where("alert_run_type=?", query.AlertRunType).Desc("heartbeat").Get(&retNode)
+	if err != nil {
+		sqlog.Error("Failed to get last node for run type "+query.AlertRunType, "error", err)
+		return err
+	}
+	if has {
+		query.Result = &retNode
+	}
+	return nil
+}
+
 // func ClusteringCleanupCheck(cmd *m.ClusteringCleanupCheckCommand) error {
 // 	sqlog.Debug("ClusteringCleanupCheck called")
 // 	lasthb := cmd.LastHeartbeat
@@ -270,7 +397,24 @@ func ClusteringCleanup(cmd *m.ClusteringCleanupCommand) error {
 	sqlog.Debug("ClusteringCleanup called")
 	lasthb := cmd.LastHeartbeat
 	var reterr error
-	txerr := inTransaction(func(sess *DBSession) error {
+	if !cmd.SkipHeartbeats {
+		reterr = cleanupHeartbeats(lasthb, cmd.FencingToken)
+	}
+	if !cmd.SkipAnnotations && reterr != m.ErrStaleFencingToken {
+		if err := cleanupAnnotations(lasthb, cmd.FencingToken); reterr == nil {
+			reterr = err
+		}
+	}
+	return reterr
+}
+
+func cleanupHeartbeats(lasthb int64, fencingToken int64) error {
+	return inTransaction(func(sess *DBSession) error {
+		if fencingToken != 0 {
+			if err := validateFencingToken(sess, fencingToken); err != nil {
+				return err
+			}
+		}
 		result, err := sess.Exec(deleteHearbeatSQL, lasthb-int64(setting.ClusteringHBRetention))
 		if err != nil {
 			sqlog.Error("Heartbeat cleanup failed", "error", err)
@@ -282,12 +426,41 @@ func ClusteringCleanup(cmd *m.ClusteringCleanupCommand) error {
 			return err
 		}
 		sqlog.Info("'active_node' table cleanup done", "rows deleted", rowsAffected)
+
+		result, err = sess.Exec(deleteAlertExecutionsSQL, lasthb-int64(setting.ClusteringHBRetention))
+		if err != nil {
+			sqlog.Error("Alert execution cleanup failed", "error", err)
+			return err
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			sqlog.Error("Alert execution cleanup failed", "error", err)
+			return err
+		}
+		sqlog.Info("'alert_execution' table cleanup done", "rows deleted", rowsAffected)
+
+		result, err = sess.Exec(deleteClusterNotificationsSQL, lasthb-int64(setting.ClusteringHBRetention))
+		if err != nil {
+			sqlog.Error("Cluster notification cleanup failed", "error", err)
+			return err
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			sqlog.Error("Cluster notification cleanup failed", "error", err)
+			return err
+		}
+		sqlog.Info("'cluster_notification' table cleanup done", "rows deleted", rowsAffected)
 		return nil
 	})
-	if txerr != nil {
-		reterr = txerr
-	}
-	txerr = inTransaction(func(sess *DBSession) error {
+}
+
+func cleanupAnnotations(lasthb int64, fencingToken int64) error {
+	return inTransaction(func(sess *DBSession) error {
+		if fencingToken != 0 {
+			if err := validateFencingToken(sess, fencingToken); err != nil {
+				return err
+			}
+		}
 		annoresult, err := sess.Exec(deleteAnnotationSQL, lasthb-int64(setting.AnnotationRetention))
 		if err != nil {
 			sqlog.Error("Annotation cleanup failed", "error", err)
@@ -299,11 +472,18 @@ func ClusteringCleanup(cmd *m.ClusteringCleanupCommand) error {
 			return err
 		}
 		sqlog.Info("'annotation' table cleanup done", "rows deleted", rowsAffected)
+
+		historyresult, err := sess.Exec(deleteAlertStateHistorySQL, lasthb-int64(setting.AnnotationRetention))
+		if err != nil {
+			sqlog.Error("Alert state history cleanup failed", "error", err)
+			return err
+		}
+		rowsAffected, err = historyresult.RowsAffected()
+		if err != nil {
+			sqlog.Error("Alert state history cleanup failed", "error", err)
+			return err
+		}
+		sqlog.Info("'alert_state_history' table cleanup done", "rows deleted", rowsAffected)
 		return nil
 	})
-	if reterr == nil && txerr != nil {
-		reterr = txerr
-	}
-	return reterr
-
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -97,5 +98,80 @@ func TestActiveNode(t *testing.T) {
 			So(cmd6.Result.AlertRunType, ShouldEqual, m.CLN_ALERT_RUN_TYPE_NORMAL)
 			So(cmd6.Result.AlertStatus, ShouldEqual, m.CLN_ALERT_STATUS_READY)
 		})
+
+		//Get nodes, history and owners for the cluster status api
+		cmd7 := m.GetLatestActiveNodesQuery{Heartbeat: hb}
+		err = GetLatestActiveNodes(&cmd7)
+		Convey("Get latest active nodes", func() {
+			So(err, ShouldBeNil)
+			So(len(cmd7.Result), ShouldEqual, 1)
+			So(cmd7.Result[0].NodeId, ShouldEqual, "10.0.0.1:3030")
+		})
+
+		cmd8 := m.GetActiveNodeHistoryQuery{NodeId: "10.0.0.1:3030", Heartbeats: 5}
+		err = GetActiveNodeHistory(&cmd8)
+		Convey("Get active node history", func() {
+			So(err, ShouldBeNil)
+			So(len(cmd8.Result), ShouldEqual, 1)
+			So(cmd8.Result[0].Heartbeat, ShouldEqual, hb)
+		})
+
+		cmd9 := m.GetActiveNodesLastCheckInQuery{}
+		err = GetActiveNodesLastCheckIn(&cmd9)
+		Convey("Get last check-in per node", func() {
+			So(err, ShouldBeNil)
+			So(cmd9.Result["10.0.0.1:3030"], ShouldEqual, hb)
+			So(cmd9.Result[nodeID], ShouldBeGreaterThan, 0)
+		})
+
+		cmd10 := m.GetLastNodeForRunTypeQuery{AlertRunType: m.CLN_ALERT_RUN_TYPE_MISSING}
+		err = GetLastNodeForRunType(&cmd10)
+		Convey("Get node processing missing alerts", func() {
+			So(err, ShouldBeNil)
+			So(cmd10.Result, ShouldNotBeNil)
+			So(cmd10.Result.NodeId, ShouldEqual, nodeID)
+		})
+
+		cmd11 := m.GetLastNodeForRunTypeQuery{AlertRunType: m.CLN_ALERT_RUN_TYPE_CLEANUP}
+		err = GetLastNodeForRunType(&cmd11)
+		Convey("No node has run cleanup", func() {
+			So(err, ShouldBeNil)
+			So(cmd11.Result, ShouldBeNil)
+		})
+
+		//Draining node replaces its check-in for the heartbeat
+		drainCmd := m.SaveActiveNodeCommand{
+			Node: &m.ActiveNode{
+				NodeId:       "10.0.0.1:3030",
+				AlertRunType: m.CLN_ALERT_RUN_TYPE_NORMAL,
+				AlertStatus:  m.CLN_ALERT_STATUS_DRAINING,
+			},
+			FetchResult: true,
+		}
+		err = InsertActiveNodeHeartbeat(&drainCmd)
+		Convey("Can check in as draining", func() {
+			So(err, ShouldBeNil)
+			So(drainCmd.Result.AlertStatus, ShouldEqual, m.CLN_ALERT_STATUS_DRAINING)
+		})
+
+		//Work is refused once its heartbeat is stale
+		Convey("Fencing token of the last heartbeat is valid", func() {
+			So(ValidateFencingToken(&m.ValidateFencingTokenQuery{Token: lastHeartbeat}), ShouldBeNil)
+		})
+		Convey("Fencing token of an older heartbeat is stale", func() {
+			err := ValidateFencingToken(&m.ValidateFencingTokenQuery{Token: lastHeartbeat - 60})
+			So(err, ShouldEqual, m.ErrStaleFencingToken)
+		})
+		Convey("Cleanup for an older heartbeat is refused", func() {
+			err := ClusteringCleanup(&m.ClusteringCleanupCommand{
+				LastHeartbeat: lastHeartbeat + int64(setting.ClusteringHBRetention) + 60,
+				FencingToken:  lastHeartbeat - 60,
+			})
+			So(err, ShouldEqual, m.ErrStaleFencingToken)
+
+			query := m.GetLatestActiveNodesQuery{Heartbeat: hb}
+			So(GetLatestActiveNodes(&query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+		})
 	})
 }
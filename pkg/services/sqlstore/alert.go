@@ -62,6 +62,22 @@ func deleteAlertByIdInternal(alertId int64, reason string, sess *DBSession) erro
 		return err
 	}
 
+	if _, err := sess.Exec("DELETE FROM alert_instance WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM alert_state_history WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM alert_notification_journal WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("DELETE FROM alert_notification_log WHERE alert_id = ?", alertId); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -152,7 +168,7 @@ func SaveAlerts(cmd *m.SaveAlertsCommand) error {
 			return err
 		}
 
-		return nil
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_ALERTS, cmd.OrgId, cmd.DashboardId)
 	})
 }
 
@@ -174,7 +190,7 @@ func upsertAlerts(existingAlerts []*m.Alert, cmd *m.SaveAlertsCommand, sess *DBS
 			if alertToUpdate.ContainsUpdates(alert) {
 				alert.Updated = time.Now()
 				alert.State = alertToUpdate.State
-				sess.MustCols("message")
+				sess.MustCols("message", "severity")
 				_, err := sess.Id(alert.Id).Update(alert)
 				if err != nil {
 					return err
@@ -370,6 +386,13 @@ func GetMissingAlerts(query *m.GetMissingAlertsQuery) error {
 		return err
 	}
 
+	//Get the last interval each alert was claimed for from the alert execution ledger
+	lastExecutions := &m.GetLastAlertExecutionsQuery{}
+	if err := GetLastAlertExecutions(lastExecutions); err != nil {
+		sqlog.Error("Could not load alert executions", "error", err)
+		return err
+	}
+
 	var currentTime = time.Unix(ts, 0)
 	sqlog.Info("currentTime", "currentTime", currentTime)
 	var expectedLastEvalTime time.Time
@@ -380,7 +403,11 @@ func GetMissingAlerts(query *m.GetMissingAlertsQuery) error {
 	for _, alert := range cmd.Result {
 		if missingAlertCount <= s.MaxMissingAlertCount { //Max no of missing alerts processed = MaxMissingAlertCount
 
+			//fall back to the eval date for alerts that have not been run through the ledger yet
 			actualEvalTime := alert.EvalDate
+			if scheduledAt, ok := lastExecutions.Result[alert.Id]; ok {
+				actualEvalTime = time.Unix(scheduledAt, 0)
+			}
 			frequency := alert.Frequency
 			sqlog.Info("alert evalDate from db", "EvalDate_Db", actualEvalTime)
 			//fmt.Println("alert evalDate from db", actualEvalTime)
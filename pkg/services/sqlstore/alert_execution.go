@@ -0,0 +1,104 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+var (
+	lastAlertExecutionsSQL   = "select alert_id, max(scheduled_at) as scheduled_at from alert_execution group by alert_id"
+	deleteAlertExecutionsSQL = "delete from alert_execution where scheduled_at < ?"
+)
+
+func init() {
+	bus.AddHandler("sql", ClaimAlertExecution)
+	bus.AddHandler("sql", FinishAlertExecution)
+	bus.AddHandler("sql", GetAlertExecutions)
+	bus.AddHandler("sql", GetLastAlertExecutions)
+}
+
+func ClaimAlertExecution(cmd *m.ClaimAlertExecutionCommand) error {
+	execution := &m.AlertExecution{
+		AlertId:     cmd.AlertId,
+		OrgId:       cmd.OrgId,
+		ScheduledAt: cmd.ScheduledAt,
+		NodeId:      cmd.NodeId,
+		StartedAt:   cmd.StartedAt,
+		Outcome:     m.ALERT_EXECUTION_OUTCOME_RUNNING,
+	}
+	err := inTransaction(func(sess *DBSession) error {
+		// the unique index on alert_id and scheduled_at fails the insert when
+		// another node claims the interval at the same time
+		has, err := sess.Where("alert_id=? and scheduled_at=?", cmd.AlertId, cmd.ScheduledAt).Get(&m.AlertExecution{})
+		if err != nil {
+			return err
+		}
+		if has {
+			return m.ErrAlertExecutionClaimed
+		}
+		_, err = sess.Insert(execution)
+		return err
+	})
+	if err != nil {
+		if err != m.ErrAlertExecutionClaimed {
+			if has, _ := x.Where("alert_id=? and scheduled_at=?", cmd.AlertId, cmd.ScheduledAt).Get(&m.AlertExecution{}); has {
+				return m.ErrAlertExecutionClaimed
+			}
+			sqlog.Error("Failed to claim alert execution", "alertId", cmd.AlertId, "scheduledAt", cmd.ScheduledAt, "error", err)
+		}
+		return err
+	}
+	cmd.Result = execution
+	return nil
+}
+
+func FinishAlertExecution(cmd *m.FinishAlertExecutionCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		execution := &m.AlertExecution{
+			FinishedAt: cmd.FinishedAt,
+			DurationMs: cmd.DurationMs,
+			Outcome:    cmd.Outcome,
+			Error:      cmd.Error,
+		}
+		_, err := sess.Id(cmd.Id).Cols("finished_at", "duration_ms", "outcome", "error").Update(execution)
+		return err
+	})
+}
+
+func GetAlertExecutions(query *m.GetAlertExecutionsQuery) error {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	sess := x.Limit(limit, 0).Desc("scheduled_at", "id")
+	if query.OrgId != 0 {
+		sess.And("org_id=?", query.OrgId)
+	}
+	if query.AlertId != 0 {
+		sess.And("alert_id=?", query.AlertId)
+	}
+	if query.NodeId != "" {
+		sess.And("node_id=?", query.NodeId)
+	}
+
+	executions := make([]*m.AlertExecution, 0)
+	if err := sess.Find(&executions); err != nil {
+		return err
+	}
+
+	query.Result = executions
+	return nil
+}
+
+func GetLastAlertExecutions(query *m.GetLastAlertExecutionsQuery) error {
+	results := make([]*m.AlertExecution, 0)
+	if err := x.Sql(lastAlertExecutionsSQL).Find(&results); err != nil {
+		sqlog.Error("Failed to get last alert executions", "error", err)
+		return err
+	}
+	query.Result = make(map[int64]int64)
+	for _, execution := range results {
+		query.Result[execution.AlertId] = execution.ScheduledAt
+	}
+	return nil
+}
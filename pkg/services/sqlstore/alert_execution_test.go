@@ -0,0 +1,84 @@
+package sqlstore
+
+import (
+	"testing"
+
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertExecutionLedger(t *testing.T) {
+	Convey("Testing alert execution ledger", t, func() {
+		InitTestDB(t)
+
+		claim := &m.ClaimAlertExecutionCommand{
+			AlertId:     1,
+			OrgId:       1,
+			ScheduledAt: 1493233560,
+			NodeId:      "node1:3000",
+			StartedAt:   1493233561,
+		}
+		err := ClaimAlertExecution(claim)
+		So(err, ShouldBeNil)
+		So(claim.Result.Id, ShouldBeGreaterThan, 0)
+		So(claim.Result.Outcome, ShouldEqual, m.ALERT_EXECUTION_OUTCOME_RUNNING)
+
+		Convey("Interval can be claimed only once", func() {
+			err := ClaimAlertExecution(&m.ClaimAlertExecutionCommand{
+				AlertId:     1,
+				OrgId:       1,
+				ScheduledAt: 1493233560,
+				NodeId:      "node2:3000",
+			})
+			So(err, ShouldEqual, m.ErrAlertExecutionClaimed)
+
+			err = ClaimAlertExecution(&m.ClaimAlertExecutionCommand{
+				AlertId:     1,
+				OrgId:       1,
+				ScheduledAt: 1493233620,
+				NodeId:      "node2:3000",
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Finished execution is recorded", func() {
+			err := FinishAlertExecution(&m.FinishAlertExecutionCommand{
+				Id:         claim.Result.Id,
+				FinishedAt: 1493233562,
+				DurationMs: 1200,
+				Outcome:    string(m.AlertStateAlerting),
+			})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertExecutionsQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertExecutions(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].NodeId, ShouldEqual, "node1:3000")
+			So(query.Result[0].DurationMs, ShouldEqual, 1200)
+			So(query.Result[0].Outcome, ShouldEqual, string(m.AlertStateAlerting))
+		})
+
+		Convey("Last execution is returned per alert", func() {
+			ClaimAlertExecution(&m.ClaimAlertExecutionCommand{AlertId: 1, OrgId: 1, ScheduledAt: 1493233620})
+			ClaimAlertExecution(&m.ClaimAlertExecutionCommand{AlertId: 2, OrgId: 1, ScheduledAt: 1493233500})
+
+			query := &m.GetLastAlertExecutionsQuery{}
+			So(GetLastAlertExecutions(query), ShouldBeNil)
+			So(query.Result[1], ShouldEqual, 1493233620)
+			So(query.Result[2], ShouldEqual, 1493233500)
+		})
+
+		Convey("Executions are removed with old heartbeats", func() {
+			err := ClusteringCleanup(&m.ClusteringCleanupCommand{
+				LastHeartbeat:   1493233620 + int64(setting.ClusteringHBRetention) + 60,
+				SkipAnnotations: true,
+			})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertExecutionsQuery{}
+			So(GetAlertExecutions(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+	})
+}
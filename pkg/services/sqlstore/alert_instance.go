@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", GetAlertInstances)
+	bus.AddHandler("sql", SaveAlertInstances)
+}
+
+func GetAlertInstances(query *m.GetAlertInstancesQuery) error {
+	instances := make([]*m.AlertInstance, 0)
+	sess := x.Where("alert_id=?", query.AlertId).Asc("id")
+	if query.OrgId != 0 {
+		sess.And("org_id=?", query.OrgId)
+	}
+	if err := sess.Find(&instances); err != nil {
+		return err
+	}
+
+	query.Result = instances
+	return nil
+}
+
+func SaveAlertInstances(cmd *m.SaveAlertInstancesCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		now := time.Now()
+		for _, instance := range cmd.Instances {
+			instance.OrgId = cmd.OrgId
+			instance.AlertId = cmd.AlertId
+			instance.Updated = now
+
+			existing := m.AlertInstance{}
+			has, err := sess.Where("alert_id=? and instance_key=?", cmd.AlertId, instance.InstanceKey).Get(&existing)
+			if err != nil {
+				return err
+			}
+
+			if !has {
+				if _, err := sess.Insert(instance); err != nil {
+					return err
+				}
+				continue
+			}
+
+			instance.Id = existing.Id
+			if _, err := sess.Id(instance.Id).Cols("state", "new_state_date", "updated").Update(instance); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertInstanceDataAccess(t *testing.T) {
+	Convey("Testing alert instance data access", t, func() {
+		InitTestDB(t)
+
+		err := SaveAlertInstances(&m.SaveAlertInstancesCommand{
+			OrgId:   1,
+			AlertId: 1,
+			Instances: []*m.AlertInstance{
+				{InstanceKey: "a", Metric: "cpu", Tags: map[string]string{"host": "server1"}, State: m.AlertStateAlerting, NewStateDate: time.Now()},
+				{InstanceKey: "b", Metric: "cpu", Tags: map[string]string{"host": "server2"}, State: m.AlertStateOK, NewStateDate: time.Now()},
+			},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("Instances are returned for the alert", func() {
+			query := &m.GetAlertInstancesQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+			So(query.Result[0].Metric, ShouldEqual, "cpu")
+			So(query.Result[0].Tags["host"], ShouldEqual, "server1")
+			So(query.Result[0].State, ShouldEqual, m.AlertStateAlerting)
+		})
+
+		Convey("Saving an instance with the same key updates its state", func() {
+			err := SaveAlertInstances(&m.SaveAlertInstancesCommand{
+				OrgId:   1,
+				AlertId: 1,
+				Instances: []*m.AlertInstance{
+					{InstanceKey: "a", Metric: "cpu", State: m.AlertStateOK, NewStateDate: time.Now()},
+				},
+			})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertInstancesQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+			So(query.Result[0].State, ShouldEqual, m.AlertStateOK)
+			So(query.Result[0].Tags["host"], ShouldEqual, "server1")
+		})
+
+		Convey("Instances are removed with their alert", func() {
+			err := DeleteAlertById(&m.DeleteAlertCommand{AlertId: 1})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertInstancesQuery{AlertId: 1}
+			So(GetAlertInstances(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+	})
+}
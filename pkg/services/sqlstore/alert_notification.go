@@ -17,6 +17,8 @@ func init() {
 	bus.AddHandler("sql", DeleteAlertNotification)
 	bus.AddHandler("sql", GetAlertNotificationsToSend)
 	bus.AddHandler("sql", GetAllAlertNotifications)
+	bus.AddHandler("sql", RecordNotificationJournal)
+	bus.AddHandler("sql", GetLatestNotification)
 }
 
 func DeleteAlertNotification(cmd *m.DeleteAlertNotificationCommand) error {
@@ -28,7 +30,8 @@ func DeleteAlertNotification(cmd *m.DeleteAlertNotificationCommand) error {
 			return err
 		}
 
-		return nil
+		_, err = sess.Exec("DELETE FROM alert_notification_journal WHERE org_id = ? AND notifier_id = ?", cmd.OrgId, cmd.Id)
+		return err
 	})
 }
 
@@ -58,7 +61,9 @@ func GetAlertNotificationsToSend(query *m.GetAlertNotificationsToSendQuery) erro
 										alert_notification.created,
 										alert_notification.updated,
 										alert_notification.settings,
-										alert_notification.is_default
+										alert_notification.is_default,
+										alert_notification.send_reminder,
+										alert_notification.frequency
 										FROM alert_notification
 	  							`)
 
@@ -96,7 +101,9 @@ func getAlertNotificationInternal(query *m.GetAlertNotificationsQuery, sess *DBS
 										alert_notification.created,
 										alert_notification.updated,
 										alert_notification.settings,
-										alert_notification.is_default
+										alert_notification.is_default,
+										alert_notification.send_reminder,
+										alert_notification.frequency
 										FROM alert_notification
 	  							`)
 
@@ -142,14 +149,21 @@ func CreateAlertNotificationCommand(cmd *m.CreateAlertNotificationCommand) error
 			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
 		}
 
+		frequency, err := parseNotificationFrequency(cmd.SendReminder, cmd.Frequency)
+		if err != nil {
+			return err
+		}
+
 		alertNotification := &m.AlertNotification{
-			OrgId:     cmd.OrgId,
-			Name:      cmd.Name,
-			Type:      cmd.Type,
-			Settings:  cmd.Settings,
-			Created:   time.Now(),
-			Updated:   time.Now(),
-			IsDefault: cmd.IsDefault,
+			OrgId:        cmd.OrgId,
+			Name:         cmd.Name,
+			Type:         cmd.Type,
+			Settings:     cmd.Settings,
+			Created:      time.Now(),
+			Updated:      time.Now(),
+			IsDefault:    cmd.IsDefault,
+			SendReminder: cmd.SendReminder,
+			Frequency:    frequency,
 		}
 
 		if _, err = sess.Insert(alertNotification); err != nil {
@@ -179,13 +193,21 @@ func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
 			return fmt.Errorf("Alert notification name %s already exists", cmd.Name)
 		}
 
+		frequency, err := parseNotificationFrequency(cmd.SendReminder, cmd.Frequency)
+		if err != nil {
+			return err
+		}
+
 		current.Updated = time.Now()
 		current.Settings = cmd.Settings
 		current.Name = cmd.Name
 		current.Type = cmd.Type
 		current.IsDefault = cmd.IsDefault
+		current.SendReminder = cmd.SendReminder
+		current.Frequency = frequency
 
-		sess.UseBool("is_default")
+		sess.UseBool("is_default", "send_reminder")
+		sess.MustCols("frequency")
 
 		if affected, err := sess.Id(cmd.Id).Update(current); err != nil {
 			return err
@@ -197,3 +219,53 @@ func UpdateAlertNotification(cmd *m.UpdateAlertNotificationCommand) error {
 		return nil
 	})
 }
+
+// parseNotificationFrequency reads the reminder frequency, which is required
+// when reminders are sent.
+func parseNotificationFrequency(sendReminder bool, frequency string) (time.Duration, error) {
+	if frequency == "" && !sendReminder {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(frequency)
+	if err != nil || duration <= 0 {
+		return 0, m.ErrNotificationFrequencyInvalid
+	}
+	return duration, nil
+}
+
+func RecordNotificationJournal(cmd *m.RecordNotificationJournalCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := sess.Exec("DELETE FROM alert_notification_journal WHERE org_id = ? AND alert_id = ? AND notifier_id = ? AND success = ?",
+			cmd.OrgId, cmd.AlertId, cmd.NotifierId, dialect.BooleanStr(cmd.Success)); err != nil {
+			return err
+		}
+
+		_, err := sess.Insert(&m.AlertNotificationJournal{
+			OrgId:      cmd.OrgId,
+			AlertId:    cmd.AlertId,
+			NotifierId: cmd.NotifierId,
+			SentAt:     cmd.SentAt,
+			Success:    cmd.Success,
+		})
+		return err
+	})
+}
+
+func GetLatestNotification(query *m.GetLatestNotificationQuery) error {
+	journal := &m.AlertNotificationJournal{}
+	sess := x.Where("org_id = ? AND alert_id = ? AND notifier_id = ?", query.OrgId, query.AlertId, query.NotifierId)
+	if query.SuccessOnly {
+		sess.And("success = ?", dialect.BooleanStr(true))
+	}
+
+	has, err := sess.Desc("sent_at").Get(journal)
+	if err != nil {
+		return err
+	}
+
+	if has {
+		query.Result = journal
+	}
+	return nil
+}
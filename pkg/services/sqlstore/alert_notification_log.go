@@ -0,0 +1,93 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SaveAlertNotificationLog)
+	bus.AddHandler("sql", MarkAlertNotificationLogReplayed)
+	bus.AddHandler("sql", GetAlertNotificationLog)
+	bus.AddHandler("sql", GetAlertNotificationLogById)
+}
+
+func SaveAlertNotificationLog(cmd *m.SaveAlertNotificationLogCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		entry := &m.AlertNotificationLog{
+			OrgId:      cmd.OrgId,
+			AlertId:    cmd.AlertId,
+			NotifierId: cmd.NotifierId,
+			Payload:    cmd.Payload,
+			Error:      cmd.Error,
+			Attempts:   cmd.Attempts,
+			Created:    time.Now(),
+		}
+
+		if _, err := sess.Insert(entry); err != nil {
+			return err
+		}
+
+		cmd.Result = entry
+		return nil
+	})
+}
+
+func MarkAlertNotificationLogReplayed(cmd *m.MarkAlertNotificationLogReplayedCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		entry := &m.AlertNotificationLog{Replayed: true, ReplayedAt: cmd.ReplayedAt}
+		affected, err := sess.Where("id=? AND org_id=?", cmd.Id, cmd.OrgId).Cols("replayed", "replayed_at").Update(entry)
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return m.ErrAlertNotificationLogNotFound
+		}
+		return nil
+	})
+}
+
+func GetAlertNotificationLog(query *m.GetAlertNotificationLogQuery) error {
+	sess := x.Where("org_id=?", query.OrgId)
+
+	if query.AlertId != 0 {
+		sess.And("alert_id=?", query.AlertId)
+	}
+
+	if query.NotifierId != 0 {
+		sess.And("notifier_id=?", query.NotifierId)
+	}
+
+	if !query.IncludeReplayed {
+		sess.And("replayed=?", dialect.BooleanStr(false))
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = m.DEFAULT_ALERT_NOTIFICATION_LOG_LIMIT
+	}
+
+	entries := make([]*m.AlertNotificationLog, 0)
+	if err := sess.Desc("id").Limit(limit).Find(&entries); err != nil {
+		return err
+	}
+
+	query.Result = entries
+	return nil
+}
+
+func GetAlertNotificationLogById(query *m.GetAlertNotificationLogByIdQuery) error {
+	entry := &m.AlertNotificationLog{}
+	has, err := x.Where("id=? AND org_id=?", query.Id, query.OrgId).Get(entry)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return m.ErrAlertNotificationLogNotFound
+	}
+
+	query.Result = entry
+	return nil
+}
@@ -0,0 +1,57 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertNotificationLogDataAccess(t *testing.T) {
+	Convey("Testing alert notification log data access", t, func() {
+		InitTestDB(t)
+
+		payload := simplejson.New()
+		payload.Set("state", "alerting")
+		cmd := &m.SaveAlertNotificationLogCommand{
+			OrgId:      1,
+			AlertId:    1,
+			NotifierId: 2,
+			Payload:    payload,
+			Error:      "connection refused",
+			Attempts:   3,
+		}
+		So(SaveAlertNotificationLog(cmd), ShouldBeNil)
+		So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+
+		So(SaveAlertNotificationLog(&m.SaveAlertNotificationLogCommand{OrgId: 1, AlertId: 2, NotifierId: 2, Error: "timeout"}), ShouldBeNil)
+
+		Convey("Failed notifications can be filtered by alert", func() {
+			query := &m.GetAlertNotificationLogQuery{OrgId: 1, AlertId: 1}
+			So(GetAlertNotificationLog(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Attempts, ShouldEqual, 3)
+			So(query.Result[0].Payload.Get("state").MustString(), ShouldEqual, "alerting")
+		})
+
+		Convey("Replayed notifications are left out by default", func() {
+			So(MarkAlertNotificationLogReplayed(&m.MarkAlertNotificationLogReplayedCommand{Id: cmd.Result.Id, OrgId: 1, ReplayedAt: time.Now()}), ShouldBeNil)
+
+			query := &m.GetAlertNotificationLogQuery{OrgId: 1}
+			So(GetAlertNotificationLog(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+
+			query = &m.GetAlertNotificationLogQuery{OrgId: 1, IncludeReplayed: true}
+			So(GetAlertNotificationLog(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+			So(query.Result[1].Replayed, ShouldBeTrue)
+		})
+
+		Convey("Entries of another org are not found", func() {
+			err := GetAlertNotificationLogById(&m.GetAlertNotificationLogByIdQuery{Id: cmd.Result.Id, OrgId: 2})
+			So(err, ShouldEqual, m.ErrAlertNotificationLogNotFound)
+		})
+	})
+}
@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	m "github.com/grafana/grafana/pkg/models"
@@ -57,6 +58,56 @@ func TestAlertNotificationSQLAccess(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(newCmd.Result.Name, ShouldEqual, "NewName")
 			})
+
+			Convey("Can update reminder frequency", func() {
+				newCmd := &m.UpdateAlertNotificationCommand{
+					Name:         "ops",
+					Type:         "email",
+					OrgId:        cmd.Result.OrgId,
+					Settings:     simplejson.New(),
+					Id:           cmd.Result.Id,
+					SendReminder: true,
+					Frequency:    "15m",
+				}
+				So(UpdateAlertNotification(newCmd), ShouldBeNil)
+
+				query := &m.GetAlertNotificationsQuery{OrgId: cmd.Result.OrgId, Id: cmd.Result.Id}
+				So(GetAlertNotifications(query), ShouldBeNil)
+				So(query.Result.SendReminder, ShouldBeTrue)
+				So(query.Result.Frequency, ShouldEqual, 15*time.Minute)
+			})
+
+			Convey("Cannot send reminders without a frequency", func() {
+				newCmd := &m.UpdateAlertNotificationCommand{
+					Name:         "ops",
+					Type:         "email",
+					OrgId:        cmd.Result.OrgId,
+					Settings:     simplejson.New(),
+					Id:           cmd.Result.Id,
+					SendReminder: true,
+				}
+				So(UpdateAlertNotification(newCmd), ShouldEqual, m.ErrNotificationFrequencyInvalid)
+			})
+		})
+
+		Convey("Notification journal keeps the last notification sent", func() {
+			query := &m.GetLatestNotificationQuery{OrgId: 1, AlertId: 1, NotifierId: 1}
+			So(GetLatestNotification(query), ShouldBeNil)
+			So(query.Result, ShouldBeNil)
+
+			So(RecordNotificationJournal(&m.RecordNotificationJournalCommand{OrgId: 1, AlertId: 1, NotifierId: 1, SentAt: 1493233560, Success: true}), ShouldBeNil)
+			So(RecordNotificationJournal(&m.RecordNotificationJournalCommand{OrgId: 1, AlertId: 1, NotifierId: 1, SentAt: 1493234460, Success: false}), ShouldBeNil)
+
+			So(GetLatestNotification(query), ShouldBeNil)
+			So(query.Result.SentAt, ShouldEqual, 1493234460)
+			So(query.Result.Success, ShouldBeFalse)
+
+			Convey("failed sends do not replace the last successful send", func() {
+				query.SuccessOnly = true
+				So(GetLatestNotification(query), ShouldBeNil)
+				So(query.Result.SentAt, ShouldEqual, 1493233560)
+				So(query.Result.Success, ShouldBeTrue)
+			})
 		})
 
 		Convey("Can search using an array of ids", func() {
@@ -0,0 +1,130 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SaveAlertRoutingPolicy)
+	bus.AddHandler("sql", DeleteAlertRoutingPolicy)
+	bus.AddHandler("sql", GetAlertRoutingPolicies)
+	bus.AddHandler("sql", GetAlertRoutingPolicyById)
+	bus.AddHandler("sql", GetRoutedNotificationIds)
+}
+
+func getAlertRoutingPolicy(sess *DBSession, id int64, orgId int64) (*m.AlertRoutingPolicy, error) {
+	policy := &m.AlertRoutingPolicy{}
+	has, err := sess.Where("id=? AND org_id=?", id, orgId).Get(policy)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, m.ErrAlertRoutingPolicyNotFound
+	}
+	return policy, nil
+}
+
+func SaveAlertRoutingPolicy(cmd *m.SaveAlertRoutingPolicyCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		policy := &m.AlertRoutingPolicy{
+			Id:              cmd.Id,
+			OrgId:           cmd.OrgId,
+			Name:            cmd.Name,
+			Position:        cmd.Position,
+			RulePattern:     cmd.RulePattern,
+			DashboardTag:    cmd.DashboardTag,
+			Severity:        cmd.Severity,
+			Labels:          cmd.Labels,
+			NotificationIds: cmd.NotificationIds,
+			Continue:        cmd.Continue,
+			Updated:         time.Now(),
+		}
+
+		if err := policy.Validate(); err != nil {
+			return err
+		}
+
+		if policy.Id == 0 {
+			policy.Created = policy.Updated
+			if _, err := sess.Insert(policy); err != nil {
+				return err
+			}
+		} else {
+			existing, err := getAlertRoutingPolicy(sess, policy.Id, cmd.OrgId)
+			if err != nil {
+				return err
+			}
+
+			policy.Created = existing.Created
+			if _, err := sess.Id(policy.Id).MustCols("position", "rule_pattern", "dashboard_tag", "severity", "labels", "continue").Update(policy); err != nil {
+				return err
+			}
+		}
+
+		cmd.Result = policy
+		return nil
+	})
+}
+
+func DeleteAlertRoutingPolicy(cmd *m.DeleteAlertRoutingPolicyCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := getAlertRoutingPolicy(sess, cmd.Id, cmd.OrgId); err != nil {
+			return err
+		}
+
+		_, err := sess.Exec("DELETE FROM alert_routing_policy WHERE id = ?", cmd.Id)
+		return err
+	})
+}
+
+func GetAlertRoutingPolicies(query *m.GetAlertRoutingPoliciesQuery) error {
+	policies := make([]*m.AlertRoutingPolicy, 0)
+	if err := x.Where("org_id=?", query.OrgId).Asc("position", "id").Find(&policies); err != nil {
+		return err
+	}
+
+	query.Result = policies
+	return nil
+}
+
+func GetAlertRoutingPolicyById(query *m.GetAlertRoutingPolicyByIdQuery) error {
+	policy, err := getAlertRoutingPolicy(newSession(), query.Id, query.OrgId)
+	if err != nil {
+		return err
+	}
+
+	query.Result = policy
+	return nil
+}
+
+func GetRoutedNotificationIds(query *m.GetRoutedNotificationIdsQuery) error {
+	policies := make([]*m.AlertRoutingPolicy, 0)
+	if err := x.Where("org_id=?", query.OrgId).Asc("position", "id").Find(&policies); err != nil {
+		return err
+	}
+
+	query.Result = make([]int64, 0)
+	var tags []string
+	for _, policy := range policies {
+		if policy.DashboardTag != "" && tags == nil {
+			var err error
+			if tags, err = getDashboardTagTerms(query.DashboardId); err != nil {
+				return err
+			}
+		}
+
+		if !policy.Matches(query.RuleName, query.Severity, query.Labels, tags) {
+			continue
+		}
+
+		query.Result = append(query.Result, policy.NotificationIds...)
+		if !policy.Continue {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package sqlstore
+
+import (
+	"testing"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRoutingPolicyDataAccess(t *testing.T) {
+	Convey("Testing alert routing policy data access", t, func() {
+		InitTestDB(t)
+
+		dash := insertTestDashboard("routed dashboard", 1, "database")
+
+		critical := &m.SaveAlertRoutingPolicyCommand{
+			OrgId:           1,
+			Name:            "critical to pager",
+			Position:        1,
+			Severity:        "critical",
+			NotificationIds: []int64{1},
+			Continue:        true,
+		}
+		So(SaveAlertRoutingPolicy(critical), ShouldBeNil)
+		So(critical.Result.Id, ShouldBeGreaterThan, 0)
+
+		database := &m.SaveAlertRoutingPolicyCommand{
+			OrgId:           1,
+			Name:            "database team",
+			Position:        2,
+			DashboardTag:    "database",
+			Labels:          map[string]string{"team": "db"},
+			NotificationIds: []int64{2, 3},
+		}
+		So(SaveAlertRoutingPolicy(database), ShouldBeNil)
+
+		So(SaveAlertRoutingPolicy(&m.SaveAlertRoutingPolicyCommand{
+			OrgId:           1,
+			Name:            "everything else",
+			Position:        3,
+			NotificationIds: []int64{4},
+		}), ShouldBeNil)
+
+		Convey("Policy without notifications is not saved", func() {
+			err := SaveAlertRoutingPolicy(&m.SaveAlertRoutingPolicyCommand{OrgId: 1, Name: "nowhere"})
+			So(err, ShouldEqual, m.ErrAlertRoutingPolicyMissingTarget)
+		})
+
+		Convey("Policies are returned in order", func() {
+			query := &m.GetAlertRoutingPoliciesQuery{OrgId: 1}
+			So(GetAlertRoutingPolicies(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 3)
+			So(query.Result[1].Name, ShouldEqual, "database team")
+			So(query.Result[1].Labels["team"], ShouldEqual, "db")
+			So(len(query.Result[1].NotificationIds), ShouldEqual, 2)
+		})
+
+		Convey("Routing continues after a policy with continue and stops at the next match", func() {
+			query := &m.GetRoutedNotificationIdsQuery{
+				OrgId:       1,
+				DashboardId: dash.Id,
+				Severity:    "critical",
+				Labels:      map[string]string{"team": "db"},
+			}
+			So(GetRoutedNotificationIds(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []int64{1, 2, 3})
+		})
+
+		Convey("Rules that match no other policy fall through to the last one", func() {
+			query := &m.GetRoutedNotificationIdsQuery{OrgId: 1, DashboardId: dash.Id, Severity: "warning"}
+			So(GetRoutedNotificationIds(query), ShouldBeNil)
+			So(query.Result, ShouldResemble, []int64{4})
+		})
+
+		Convey("Policy can be updated and deleted", func() {
+			database.Id = database.Result.Id
+			database.DashboardTag = ""
+			So(SaveAlertRoutingPolicy(database), ShouldBeNil)
+
+			query := &m.GetAlertRoutingPolicyByIdQuery{Id: database.Id, OrgId: 1}
+			So(GetAlertRoutingPolicyById(query), ShouldBeNil)
+			So(query.Result.DashboardTag, ShouldEqual, "")
+
+			So(DeleteAlertRoutingPolicy(&m.DeleteAlertRoutingPolicyCommand{Id: database.Id, OrgId: 2}), ShouldEqual, m.ErrAlertRoutingPolicyNotFound)
+			So(DeleteAlertRoutingPolicy(&m.DeleteAlertRoutingPolicyCommand{Id: database.Id, OrgId: 1}), ShouldBeNil)
+			So(GetAlertRoutingPolicyById(query), ShouldEqual, m.ErrAlertRoutingPolicyNotFound)
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", SaveAlertRule)
+	bus.AddHandler("sql", DeleteAlertRule)
+	bus.AddHandler("sql", GetAlertRules)
+	bus.AddHandler("sql", GetAlertRuleById)
+}
+
+func getAlertRule(sess *DBSession, id int64, orgId int64) (*m.Alert, error) {
+	alert := &m.Alert{}
+	has, err := sess.Where("id=? AND org_id=? AND dashboard_id=0 AND panel_id=0", id, orgId).Get(alert)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, m.ErrAlertRuleNotFound
+	}
+	return alert, nil
+}
+
+func SaveAlertRule(cmd *m.SaveAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		alert := cmd.Alert
+		alert.OrgId = cmd.OrgId
+		alert.DashboardId = 0
+		alert.PanelId = 0
+		alert.Updated = time.Now()
+
+		if alert.Id == 0 {
+			alert.Created = alert.Updated
+			alert.State = m.AlertStatePending
+			alert.NewStateDate = alert.Updated
+			if _, err := sess.Insert(alert); err != nil {
+				return err
+			}
+			sqlog.Debug("Alert rule inserted", "name", alert.Name, "id", alert.Id)
+		} else {
+			existing, err := getAlertRule(sess, alert.Id, cmd.OrgId)
+			if err != nil {
+				return err
+			}
+
+			alert.State = existing.State
+			alert.NewStateDate = existing.NewStateDate
+			alert.Created = existing.Created
+			if _, err := sess.Id(alert.Id).MustCols("message", "severity").Update(alert); err != nil {
+				return err
+			}
+			sqlog.Debug("Alert rule updated", "name", alert.Name, "id", alert.Id)
+		}
+
+		cmd.Result = alert
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_ALERTS, cmd.OrgId, alert.Id)
+	})
+}
+
+func DeleteAlertRule(cmd *m.DeleteAlertRuleCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		if _, err := getAlertRule(sess, cmd.Id, cmd.OrgId); err != nil {
+			return err
+		}
+
+		if err := deleteAlertByIdInternal(cmd.Id, "DeleteAlertRuleCommand", sess); err != nil {
+			return err
+		}
+
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_ALERTS, cmd.OrgId, cmd.Id)
+	})
+}
+
+func GetAlertRules(query *m.GetAlertRulesQuery) error {
+	alerts := make([]*m.Alert, 0)
+	if err := x.Where("org_id=? AND dashboard_id=0 AND panel_id=0", query.OrgId).Asc("name").Find(&alerts); err != nil {
+		return err
+	}
+
+	query.Result = alerts
+	return nil
+}
+
+func GetAlertRuleById(query *m.GetAlertRuleByIdQuery) error {
+	alert, err := getAlertRule(newSession(), query.Id, query.OrgId)
+	if err != nil {
+		return err
+	}
+
+	query.Result = alert
+	return nil
+}
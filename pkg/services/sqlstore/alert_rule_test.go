@@ -0,0 +1,72 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertRuleDataAccess(t *testing.T) {
+	Convey("Testing standalone alert rule data access", t, func() {
+		InitTestDB(t)
+
+		dash := insertTestDashboard("dashboard with alert", 1, "alert")
+		err := SaveAlerts(&m.SaveAlertsCommand{
+			DashboardId: dash.Id,
+			OrgId:       1,
+			Alerts: []*m.Alert{
+				{DashboardId: dash.Id, PanelId: 1, OrgId: 1, Name: "panel alert", Settings: simplejson.New()},
+			},
+		})
+		So(err, ShouldBeNil)
+
+		cmd := &m.SaveAlertRuleCommand{
+			OrgId: 1,
+			Alert: &m.Alert{Name: "cpu usage", Message: "cpu is high", Frequency: 60, Settings: simplejson.New()},
+		}
+		So(SaveAlertRule(cmd), ShouldBeNil)
+		So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+		So(cmd.Result.State, ShouldEqual, m.AlertStatePending)
+
+		Convey("Only standalone rules are returned", func() {
+			query := &m.GetAlertRulesQuery{OrgId: 1}
+			So(GetAlertRules(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].Name, ShouldEqual, "cpu usage")
+		})
+
+		Convey("Rule can be updated", func() {
+			err := SaveAlertRule(&m.SaveAlertRuleCommand{
+				OrgId: 1,
+				Alert: &m.Alert{Id: cmd.Result.Id, Name: "cpu usage", Message: "", Frequency: 30, Settings: simplejson.New()},
+			})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertRuleByIdQuery{Id: cmd.Result.Id, OrgId: 1}
+			So(GetAlertRuleById(query), ShouldBeNil)
+			So(query.Result.Frequency, ShouldEqual, 30)
+			So(query.Result.Message, ShouldEqual, "")
+			So(query.Result.State, ShouldEqual, m.AlertStatePending)
+		})
+
+		Convey("Panel alerts and rules of other orgs cannot be changed", func() {
+			alerts := &m.GetAlertsQuery{OrgId: 1, DashboardId: dash.Id}
+			So(HandleAlertsQuery(alerts), ShouldBeNil)
+
+			err := SaveAlertRule(&m.SaveAlertRuleCommand{OrgId: 1, Alert: &m.Alert{Id: alerts.Result[0].Id, Name: "panel alert"}})
+			So(err, ShouldEqual, m.ErrAlertRuleNotFound)
+
+			err = DeleteAlertRule(&m.DeleteAlertRuleCommand{Id: cmd.Result.Id, OrgId: 2})
+			So(err, ShouldEqual, m.ErrAlertRuleNotFound)
+		})
+
+		Convey("Rule can be deleted", func() {
+			So(DeleteAlertRule(&m.DeleteAlertRuleCommand{Id: cmd.Result.Id, OrgId: 1}), ShouldBeNil)
+
+			query := &m.GetAlertRuleByIdQuery{Id: cmd.Result.Id, OrgId: 1}
+			So(GetAlertRuleById(query), ShouldEqual, m.ErrAlertRuleNotFound)
+		})
+	})
+}
@@ -0,0 +1,138 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+func init() {
+	bus.AddHandler("sql", CreateAlertSilence)
+	bus.AddHandler("sql", ExpireAlertSilence)
+	bus.AddHandler("sql", GetAlertSilences)
+	bus.AddHandler("sql", GetActiveAlertSilences)
+}
+
+func CreateAlertSilence(cmd *m.CreateAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		now := time.Now()
+		silence := &m.AlertSilence{
+			OrgId:        cmd.OrgId,
+			AlertId:      cmd.AlertId,
+			DashboardId:  cmd.DashboardId,
+			DashboardTag: cmd.DashboardTag,
+			RulePattern:  cmd.RulePattern,
+			Comment:      cmd.Comment,
+			StartsAt:     cmd.StartsAt,
+			EndsAt:       cmd.EndsAt,
+			Recurrence:   cmd.Recurrence,
+			CreatedBy:    cmd.UserId,
+			Created:      now,
+			Updated:      now,
+		}
+		if silence.StartsAt.IsZero() {
+			silence.StartsAt = now
+		}
+
+		if err := silence.Validate(); err != nil {
+			return err
+		}
+
+		if _, err := sess.Insert(silence); err != nil {
+			return err
+		}
+
+		cmd.Result = silence
+		return nil
+	})
+}
+
+func ExpireAlertSilence(cmd *m.ExpireAlertSilenceCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		silence := &m.AlertSilence{}
+		has, err := sess.Where("id=? and org_id=?", cmd.Id, cmd.OrgId).Get(silence)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return m.ErrAlertSilenceNotFound
+		}
+
+		now := time.Now()
+		if silence.ExpiresAt.IsZero() || silence.ExpiresAt.After(now) {
+			silence.ExpiresAt = now
+			silence.Updated = now
+			if _, err := sess.Id(silence.Id).Cols("expires_at", "updated").Update(silence); err != nil {
+				return err
+			}
+		}
+
+		cmd.Result = silence
+		return nil
+	})
+}
+
+func GetAlertSilences(query *m.GetAlertSilencesQuery) error {
+	silences := make([]*m.AlertSilence, 0)
+	if err := x.Where("org_id=?", query.OrgId).Desc("id").Find(&silences); err != nil {
+		return err
+	}
+
+	if query.Active {
+		now := time.Now()
+		active := make([]*m.AlertSilence, 0)
+		for _, silence := range silences {
+			if silence.IsActive(now) {
+				active = append(active, silence)
+			}
+		}
+		silences = active
+	}
+
+	query.Result = silences
+	return nil
+}
+
+func GetActiveAlertSilences(query *m.GetActiveAlertSilencesQuery) error {
+	silences := make([]*m.AlertSilence, 0)
+	if err := x.Where("org_id=?", query.OrgId).Asc("id").Find(&silences); err != nil {
+		return err
+	}
+
+	query.Result = make([]*m.AlertSilence, 0)
+	var tags []string
+	for _, silence := range silences {
+		if !silence.IsActive(query.Now) {
+			continue
+		}
+
+		if silence.DashboardTag != "" && tags == nil {
+			var err error
+			if tags, err = getDashboardTagTerms(query.DashboardId); err != nil {
+				return err
+			}
+		}
+
+		if silence.Matches(query.AlertId, query.DashboardId, query.RuleName, tags) {
+			query.Result = append(query.Result, silence)
+		}
+	}
+
+	return nil
+}
+
+// getDashboardTagTerms returns the tags of the dashboard, an empty slice if
+// it has none.
+func getDashboardTagTerms(dashboardId int64) ([]string, error) {
+	dashTags := make([]*DashboardTag, 0)
+	if err := x.Where("dashboard_id=?", dashboardId).Find(&dashTags); err != nil {
+		return nil, err
+	}
+
+	terms := make([]string, 0)
+	for _, tag := range dashTags {
+		terms = append(terms, tag.Term)
+	}
+	return terms, nil
+}
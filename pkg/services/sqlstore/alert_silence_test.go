@@ -0,0 +1,60 @@
+package sqlstore
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertSilenceDataAccess(t *testing.T) {
+	Convey("Testing alert silence data access", t, func() {
+		InitTestDB(t)
+
+		dash := insertTestDashboard("silenced dashboard", 1, "prod")
+		now := time.Now()
+
+		cmd := &m.CreateAlertSilenceCommand{
+			OrgId:        1,
+			DashboardTag: "prod",
+			StartsAt:     now.Add(-time.Hour),
+			EndsAt:       now.Add(time.Hour),
+		}
+		So(CreateAlertSilence(cmd), ShouldBeNil)
+		So(cmd.Result.Id, ShouldBeGreaterThan, 0)
+
+		Convey("Silence without a matcher is not created", func() {
+			err := CreateAlertSilence(&m.CreateAlertSilenceCommand{OrgId: 1, EndsAt: now.Add(time.Hour)})
+			So(err, ShouldEqual, m.ErrAlertSilenceMissingMatcher)
+		})
+
+		Convey("Silence matches alerts on dashboards with the tag", func() {
+			query := &m.GetActiveAlertSilencesQuery{OrgId: 1, AlertId: 1, DashboardId: dash.Id, Now: now}
+			So(GetActiveAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+
+			query = &m.GetActiveAlertSilencesQuery{OrgId: 1, AlertId: 1, DashboardId: dash.Id + 1, Now: now}
+			So(GetActiveAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+
+		Convey("Expired silence is not active", func() {
+			expire := &m.ExpireAlertSilenceCommand{Id: cmd.Result.Id, OrgId: 1}
+			So(ExpireAlertSilence(expire), ShouldBeNil)
+
+			query := &m.GetAlertSilencesQuery{OrgId: 1, Active: true}
+			So(GetAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+
+			query = &m.GetAlertSilencesQuery{OrgId: 1}
+			So(GetAlertSilences(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+		})
+
+		Convey("Expiring a silence of another org fails", func() {
+			err := ExpireAlertSilence(&m.ExpireAlertSilenceCommand{Id: cmd.Result.Id, OrgId: 2})
+			So(err, ShouldEqual, m.ErrAlertSilenceNotFound)
+		})
+	})
+}
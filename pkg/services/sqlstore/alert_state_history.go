@@ -0,0 +1,89 @@
+package sqlstore
+
+import (
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+)
+
+var deleteAlertStateHistorySQL = "delete from alert_state_history where epoch < ?"
+
+func init() {
+	bus.AddHandler("sql", SaveAlertStateHistory)
+	bus.AddHandler("sql", GetAlertStateHistory)
+}
+
+func SaveAlertStateHistory(cmd *m.SaveAlertStateHistoryCommand) error {
+	return inTransaction(func(sess *DBSession) error {
+		entry := &m.AlertStateHistory{
+			OrgId:       cmd.OrgId,
+			AlertId:     cmd.AlertId,
+			DashboardId: cmd.DashboardId,
+			PanelId:     cmd.PanelId,
+			AlertName:   cmd.AlertName,
+			NodeId:      cmd.NodeId,
+			PrevState:   cmd.PrevState,
+			NewState:    cmd.NewState,
+			Data:        cmd.Data,
+			Epoch:       cmd.Epoch,
+		}
+
+		if _, err := sess.Insert(entry); err != nil {
+			return err
+		}
+
+		cmd.Result = entry
+		return nil
+	})
+}
+
+func GetAlertStateHistory(query *m.GetAlertStateHistoryQuery) error {
+	sess := x.Where("org_id=?", query.OrgId)
+
+	if query.AlertId != 0 {
+		sess.And("alert_id=?", query.AlertId)
+	}
+
+	if query.DashboardId != 0 {
+		sess.And("dashboard_id=?", query.DashboardId)
+	}
+
+	if query.NodeId != "" {
+		sess.And("node_id=?", query.NodeId)
+	}
+
+	if len(query.State) > 0 {
+		sess.In("new_state", query.State)
+	}
+
+	if query.From > 0 {
+		sess.And("epoch>=?", query.From)
+	}
+
+	if query.To > 0 {
+		sess.And("epoch<=?", query.To)
+	}
+
+	if query.Before > 0 {
+		sess.And("id<?", query.Before)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = m.DEFAULT_ALERT_STATE_HISTORY_LIMIT
+	}
+
+	// one more than the page tells if there is a next page
+	history := make([]*m.AlertStateHistory, 0)
+	if err := sess.Desc("id").Limit(limit + 1).Find(&history); err != nil {
+		return err
+	}
+
+	query.NextCursor = 0
+	if len(history) > limit {
+		history = history[:limit]
+		query.NextCursor = history[limit-1].Id
+	}
+
+	query.Result = history
+	return nil
+}
@@ -0,0 +1,84 @@
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	m "github.com/grafana/grafana/pkg/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAlertStateHistoryDataAccess(t *testing.T) {
+	Convey("Testing alert state history data access", t, func() {
+		InitTestDB(t)
+
+		states := []m.AlertStateType{m.AlertStateAlerting, m.AlertStateOK, m.AlertStateAlerting, m.AlertStateNoData, m.AlertStateOK}
+		prev := m.AlertStateOK
+		for i, state := range states {
+			err := SaveAlertStateHistory(&m.SaveAlertStateHistoryCommand{
+				OrgId:       1,
+				AlertId:     1,
+				DashboardId: 1,
+				NodeId:      "node1:3000",
+				PrevState:   prev,
+				NewState:    state,
+				Data:        simplejson.NewFromAny(map[string]interface{}{"noData": state == m.AlertStateNoData}),
+				Epoch:       int64(1493233560 + i*60),
+			})
+			So(err, ShouldBeNil)
+			prev = state
+		}
+		SaveAlertStateHistory(&m.SaveAlertStateHistoryCommand{OrgId: 1, AlertId: 2, DashboardId: 2, NodeId: "node2:3000", NewState: m.AlertStateAlerting, Epoch: 1493233560})
+		SaveAlertStateHistory(&m.SaveAlertStateHistoryCommand{OrgId: 2, AlertId: 3, NewState: m.AlertStateAlerting, Epoch: 1493233560})
+
+		Convey("History is returned newest first with a cursor to the next page", func() {
+			query := &m.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1, Limit: 2}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+			So(query.Result[0].NewState, ShouldEqual, m.AlertStateOK)
+			So(query.Result[1].NewState, ShouldEqual, m.AlertStateNoData)
+			So(query.Result[1].Data.Get("noData").MustBool(), ShouldBeTrue)
+			So(query.NextCursor, ShouldEqual, query.Result[1].Id)
+
+			query = &m.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1, Limit: 2, Before: query.NextCursor}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+			So(query.Result[0].NewState, ShouldEqual, m.AlertStateAlerting)
+
+			query = &m.GetAlertStateHistoryQuery{OrgId: 1, AlertId: 1, Limit: 2, Before: query.NextCursor}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.NextCursor, ShouldEqual, 0)
+		})
+
+		Convey("History can be filtered", func() {
+			query := &m.GetAlertStateHistoryQuery{OrgId: 1, State: []string{"alerting"}}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 3)
+
+			query = &m.GetAlertStateHistoryQuery{OrgId: 1, From: 1493233620, To: 1493233680}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 2)
+
+			query = &m.GetAlertStateHistoryQuery{OrgId: 1, NodeId: "node2:3000"}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+			So(query.Result[0].AlertId, ShouldEqual, 2)
+
+			query = &m.GetAlertStateHistoryQuery{OrgId: 1, DashboardId: 1}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 5)
+		})
+
+		Convey("History is removed with the alert", func() {
+			err := inTransaction(func(sess *DBSession) error {
+				return deleteAlertByIdInternal(1, "test", sess)
+			})
+			So(err, ShouldBeNil)
+
+			query := &m.GetAlertStateHistoryQuery{OrgId: 1}
+			So(GetAlertStateHistory(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 1)
+		})
+	})
+}
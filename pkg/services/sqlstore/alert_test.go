@@ -252,6 +252,22 @@ func TestAlertingDataAccess(t *testing.T) {
 				So(err1, ShouldBeNil)
 				So(len(queryForMissedAlerts.Result), ShouldEqual, 1)
 			})
+
+			Convey("Get Missed Alerts from the alert execution ledger", func() {
+				So(err, ShouldBeNil)
+
+				// alert 5 ran recently, alert 4 has not run for 30 minutes
+				err1 := ClaimAlertExecution(&m.ClaimAlertExecutionCommand{AlertId: multipleAlerts[1].Id, OrgId: 1, ScheduledAt: actualEvalTime5.Unix()})
+				So(err1, ShouldBeNil)
+				err1 = ClaimAlertExecution(&m.ClaimAlertExecutionCommand{AlertId: multipleAlerts[0].Id, OrgId: 1, ScheduledAt: actualEvalTime2.Unix()})
+				So(err1, ShouldBeNil)
+
+				queryForMissedAlerts := m.GetMissingAlertsQuery{}
+				err1 = GetMissingAlerts(&queryForMissedAlerts)
+				So(err1, ShouldBeNil)
+				So(len(queryForMissedAlerts.Result), ShouldEqual, 1)
+				So(queryForMissedAlerts.Result[0].Id, ShouldEqual, multipleAlerts[0].Id)
+			})
 		})
 
 		Convey("When dashboard is removed", func() {
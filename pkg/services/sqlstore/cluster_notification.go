@@ -0,0 +1,42 @@
+package sqlstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+var deleteClusterNotificationsSQL = "delete from cluster_notification where created < ?"
+
+func init() {
+	bus.AddHandler("sql", GetClusterNotifications)
+}
+
+// addClusterNotification records in the transaction of a change that the
+// cluster nodes have to evict the changed item from their caches.
+func addClusterNotification(sess *DBSession, notificationType string, orgId int64, itemId int64) error {
+	if !setting.ClusteringEnabled {
+		return nil
+	}
+	notification := &m.ClusterNotification{
+		NodeId:  fmt.Sprintf("%v:%v", setting.InstanceName, setting.HttpPort),
+		Type:    notificationType,
+		OrgId:   orgId,
+		ItemId:  itemId,
+		Created: time.Now().Unix(),
+	}
+	_, err := sess.Insert(notification)
+	return err
+}
+
+func GetClusterNotifications(query *m.GetClusterNotificationsQuery) error {
+	notifications := make([]*m.ClusterNotification, 0)
+	if err := x.Where("created >= ?", query.Since).Asc("id").Find(&notifications); err != nil {
+		return err
+	}
+	query.Result = notifications
+	return nil
+}
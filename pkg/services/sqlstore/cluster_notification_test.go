@@ -0,0 +1,70 @@
+package sqlstore
+
+import (
+	"testing"
+
+	m "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/setting"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClusterNotifications(t *testing.T) {
+	Convey("Testing cluster notifications", t, func() {
+		InitTestDB(t)
+		prevClusteringEnabled := setting.ClusteringEnabled
+		defer func() { setting.ClusteringEnabled = prevClusteringEnabled }()
+
+		Convey("Changes are recorded for the cluster nodes", func() {
+			setting.ClusteringEnabled = true
+			dash := insertTestDashboard("cluster dash", 1)
+			err := SaveAlerts(&m.SaveAlertsCommand{OrgId: 1, DashboardId: dash.Id, Alerts: []*m.Alert{}})
+			So(err, ShouldBeNil)
+			err = DeleteDataSourceById(&m.DeleteDataSourceByIdCommand{Id: 3, OrgId: 1})
+			So(err, ShouldBeNil)
+
+			query := &m.GetClusterNotificationsQuery{}
+			So(GetClusterNotifications(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 3)
+			So(query.Result[0].Type, ShouldEqual, m.CLUSTER_NOTIFICATION_TYPE_DASHBOARD)
+			So(query.Result[0].ItemId, ShouldEqual, dash.Id)
+			So(query.Result[1].Type, ShouldEqual, m.CLUSTER_NOTIFICATION_TYPE_ALERTS)
+			So(query.Result[2].Type, ShouldEqual, m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE)
+			So(query.Result[2].ItemId, ShouldEqual, 3)
+
+			Convey("Notifications are removed with old heartbeats", func() {
+				err := ClusteringCleanup(&m.ClusteringCleanupCommand{
+					LastHeartbeat:   query.Result[2].Created + int64(setting.ClusteringHBRetention) + 60,
+					SkipAnnotations: true,
+				})
+				So(err, ShouldBeNil)
+
+				query := &m.GetClusterNotificationsQuery{}
+				So(GetClusterNotifications(query), ShouldBeNil)
+				So(len(query.Result), ShouldEqual, 0)
+			})
+		})
+
+		Convey("Deleting a datasource by name records its id", func() {
+			setting.ClusteringEnabled = true
+			add := &m.AddDataSourceCommand{OrgId: 1, Name: "cluster ds", Type: m.DS_GRAPHITE, Access: m.DS_ACCESS_DIRECT, Url: "http://test"}
+			So(AddDataSource(add), ShouldBeNil)
+			err := DeleteDataSourceByName(&m.DeleteDataSourceByNameCommand{Name: "cluster ds", OrgId: 1})
+			So(err, ShouldBeNil)
+
+			query := &m.GetClusterNotificationsQuery{}
+			So(GetClusterNotifications(query), ShouldBeNil)
+			last := query.Result[len(query.Result)-1]
+			So(last.Type, ShouldEqual, m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE)
+			So(last.ItemId, ShouldEqual, add.Result.Id)
+		})
+
+		Convey("Changes are not recorded without clustering", func() {
+			setting.ClusteringEnabled = false
+			insertTestDashboard("single dash", 1)
+
+			query := &m.GetClusterNotificationsQuery{}
+			So(GetClusterNotifications(query), ShouldBeNil)
+			So(len(query.Result), ShouldEqual, 0)
+		})
+	})
+}
@@ -126,6 +126,10 @@ func SaveDashboard(cmd *m.SaveDashboardCommand) error {
 			}
 		}
 
+		if err := addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_DASHBOARD, dash.OrgId, dash.Id); err != nil {
+			return err
+		}
+
 		cmd.Result = dash
 
 		return err
@@ -274,7 +278,7 @@ func DeleteDashboard(cmd *m.DeleteDashboardCommand) error {
 			return nil
 		}
 
-		return nil
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_DASHBOARD, dashboard.OrgId, dashboard.Id)
 	})
 }
 
@@ -52,16 +52,26 @@ func GetDataSources(query *m.GetDataSourcesQuery) error {
 func DeleteDataSourceById(cmd *m.DeleteDataSourceByIdCommand) error {
 	return inTransaction(func(sess *DBSession) error {
 		var rawSql = "DELETE FROM data_source WHERE id=? and org_id=?"
-		_, err := sess.Exec(rawSql, cmd.Id, cmd.OrgId)
-		return err
+		if _, err := sess.Exec(rawSql, cmd.Id, cmd.OrgId); err != nil {
+			return err
+		}
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE, cmd.OrgId, cmd.Id)
 	})
 }
 
 func DeleteDataSourceByName(cmd *m.DeleteDataSourceByNameCommand) error {
 	return inTransaction(func(sess *DBSession) error {
-		var rawSql = "DELETE FROM data_source WHERE name=? and org_id=?"
-		_, err := sess.Exec(rawSql, cmd.Name, cmd.OrgId)
-		return err
+		existing := m.DataSource{OrgId: cmd.OrgId, Name: cmd.Name}
+		has, err := sess.Get(&existing)
+		if err != nil || !has {
+			return err
+		}
+
+		var rawSql = "DELETE FROM data_source WHERE id=? and org_id=?"
+		if _, err := sess.Exec(rawSql, existing.Id, cmd.OrgId); err != nil {
+			return err
+		}
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE, cmd.OrgId, existing.Id)
 	})
 }
 
@@ -151,7 +161,9 @@ func UpdateDataSource(cmd *m.UpdateDataSourceCommand) error {
 			return err
 		}
 
-		err = updateIsDefaultFlag(ds, sess)
-		return err
+		if err := updateIsDefaultFlag(ds, sess); err != nil {
+			return err
+		}
+		return addClusterNotification(sess, m.CLUSTER_NOTIFICATION_TYPE_DATASOURCE, ds.OrgId, ds.Id)
 	})
 }
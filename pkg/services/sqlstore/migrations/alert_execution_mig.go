@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertExecutionMigration(mg *Migrator) {
+	alertExecution := Table{
+		Name: "alert_execution",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "scheduled_at", Type: DB_BigInt, Nullable: false},
+			{Name: "node_id", Type: DB_Varchar, Length: 128, Nullable: false},
+			{Name: "started_at", Type: DB_BigInt, Nullable: false},
+			{Name: "finished_at", Type: DB_BigInt, Nullable: false},
+			{Name: "duration_ms", Type: DB_BigInt, Nullable: false},
+			{Name: "outcome", Type: DB_Varchar, Length: 32, Nullable: false},
+			{Name: "error", Type: DB_Text, Nullable: true},
+		},
+		Indices: []*Index{
+			{Cols: []string{"alert_id", "scheduled_at"}, Type: UniqueIndex},
+			{Cols: []string{"scheduled_at"}},
+		},
+	}
+	mg.AddMigration("create alert_execution table", NewAddTableMigration(alertExecution))
+	mg.AddMigration("add unique index alert_execution.alert_id_scheduled_at", NewAddIndexMigration(alertExecution, alertExecution.Indices[0]))
+	mg.AddMigration("add index alert_execution.scheduled_at", NewAddIndexMigration(alertExecution, alertExecution.Indices[1]))
+}
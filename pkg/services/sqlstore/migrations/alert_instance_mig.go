@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertInstanceMigration(mg *Migrator) {
+	alertInstance := Table{
+		Name: "alert_instance",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "instance_key", Type: DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "metric", Type: DB_Text, Nullable: false},
+			{Name: "tags", Type: DB_Text, Nullable: true},
+			{Name: "state", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "new_state_date", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"alert_id", "instance_key"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "alert_id"}},
+		},
+	}
+	mg.AddMigration("create alert_instance table", NewAddTableMigration(alertInstance))
+	mg.AddMigration("add unique index alert_instance.alert_id_instance_key", NewAddIndexMigration(alertInstance, alertInstance.Indices[0]))
+	mg.AddMigration("add index alert_instance.org_id_alert_id", NewAddIndexMigration(alertInstance, alertInstance.Indices[1]))
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertNotificationLogMigration(mg *Migrator) {
+	alertNotificationLog := Table{
+		Name: "alert_notification_log",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "notifier_id", Type: DB_BigInt, Nullable: false},
+			{Name: "payload", Type: DB_Text, Nullable: true},
+			{Name: "error", Type: DB_Text, Nullable: false},
+			{Name: "attempts", Type: DB_Int, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "replayed", Type: DB_Bool, Nullable: false},
+			{Name: "replayed_at", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id"}},
+		},
+	}
+	mg.AddMigration("create alert_notification_log table", NewAddTableMigration(alertNotificationLog))
+	mg.AddMigration("add index alert_notification_log.org_id_alert_id", NewAddIndexMigration(alertNotificationLog, alertNotificationLog.Indices[0]))
+}
@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertRoutingPolicyMigration(mg *Migrator) {
+	alertRoutingPolicy := Table{
+		Name: "alert_routing_policy",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "position", Type: DB_BigInt, Nullable: false},
+			{Name: "rule_pattern", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "dashboard_tag", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "severity", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "labels", Type: DB_Text, Nullable: true},
+			{Name: "notification_ids", Type: DB_Text, Nullable: false},
+			{Name: "continue", Type: DB_Bool, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}},
+		},
+	}
+	mg.AddMigration("create alert_routing_policy table", NewAddTableMigration(alertRoutingPolicy))
+	mg.AddMigration("add index alert_routing_policy.org_id", NewAddIndexMigration(alertRoutingPolicy, alertRoutingPolicy.Indices[0]))
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertSilenceMigration(mg *Migrator) {
+	alertSilence := Table{
+		Name: "alert_silence",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_tag", Type: DB_NVarchar, Length: 50, Nullable: false},
+			{Name: "rule_pattern", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "comment", Type: DB_Text, Nullable: false},
+			{Name: "starts_at", Type: DB_DateTime, Nullable: false},
+			{Name: "ends_at", Type: DB_DateTime, Nullable: false},
+			{Name: "recurrence", Type: DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "expires_at", Type: DB_DateTime, Nullable: true},
+			{Name: "created_by", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id"}},
+		},
+	}
+	mg.AddMigration("create alert_silence table", NewAddTableMigration(alertSilence))
+	mg.AddMigration("add index alert_silence.org_id", NewAddIndexMigration(alertSilence, alertSilence.Indices[0]))
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addAlertStateHistoryMigration(mg *Migrator) {
+	alertStateHistory := Table{
+		Name: "alert_state_history",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_id", Type: DB_BigInt, Nullable: false},
+			{Name: "panel_id", Type: DB_BigInt, Nullable: false},
+			{Name: "alert_name", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "node_id", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "prev_state", Type: DB_NVarchar, Length: 25, Nullable: false},
+			{Name: "new_state", Type: DB_NVarchar, Length: 25, Nullable: false},
+			{Name: "data", Type: DB_Text, Nullable: true},
+			{Name: "epoch", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "alert_id"}},
+			{Cols: []string{"org_id", "epoch"}},
+		},
+	}
+	mg.AddMigration("create alert_state_history table", NewAddTableMigration(alertStateHistory))
+	mg.AddMigration("add index alert_state_history.org_id_alert_id", NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[0]))
+	mg.AddMigration("add index alert_state_history.org_id_epoch", NewAddIndexMigration(alertStateHistory, alertStateHistory.Indices[1]))
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	. "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+func addClusterNotificationMigration(mg *Migrator) {
+	clusterNotification := Table{
+		Name: "cluster_notification",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "node_id", Type: DB_Varchar, Length: 128, Nullable: false},
+			{Name: "type", Type: DB_Varchar, Length: 32, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "item_id", Type: DB_BigInt, Nullable: false},
+			{Name: "created", Type: DB_BigInt, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"created"}},
+		},
+	}
+	mg.AddMigration("create cluster_notification table", NewAddTableMigration(clusterNotification))
+	mg.AddMigration("add index cluster_notification.created", NewAddIndexMigration(clusterNotification, clusterNotification.Indices[0]))
+}
@@ -10,6 +10,13 @@ import . "github.com/grafana/grafana/pkg/services/sqlstore/migrator"
 func AddMigrations(mg *Migrator) {
 	addMigrationLogMigrations(mg)
 	addActiveNodeMigration(mg)
+	addAlertExecutionMigration(mg)
+	addClusterNotificationMigration(mg)
+	addAlertInstanceMigration(mg)
+	addAlertSilenceMigration(mg)
+	addAlertStateHistoryMigration(mg)
+	addAlertRoutingPolicyMigration(mg)
+	addAlertNotificationLogMigration(mg)
 	addUserMigrations(mg)
 	addTempUserMigrations(mg)
 	addStarMigrations(mg)
@@ -41,6 +41,9 @@ const (
 	DEFAULT_MISSING_ALERTS_SCHEDULAR_TIME_MINUTES int    = 10      //Range from [1-60] to represent 60 minutes
 	DEFAULT_CLUSTERING_CLEANUP_PERIOD             int    = 24      // Range from [1-24] to represent 24 hrs.
 	DEFAULT_CLUSTERING_HB_RETENSION_PERIOD        int    = 86400   // 1 day
+	DEFAULT_CLUSTERING_VIRTUAL_NODES              int    = 200     // virtual nodes per node on the alert hash ring
+	DEFAULT_CLUSTERING_DRAIN_TIMEOUT              int    = 60      // seconds to wait for in-flight alerts on shutdown
+	DEFAULT_CLUSTERING_INVALIDATION_POLL          int    = 5       // seconds between reads of the cache invalidations of peers
 	DEFAULT_ANNOTATION_RETENSION_PERIOD           int    = 1209600 // 14 days
 )
 
@@ -163,8 +166,11 @@ var (
 	Quota QuotaSettings
 
 	// Alerting
-	AlertingEnabled bool
-	ExecuteAlerts   bool
+	AlertingEnabled                  bool
+	ExecuteAlerts                    bool
+	AlertingNotificationMaxAttempts  int
+	AlertingNotificationRetryBackoff int
+	AlertingNotificationTimeout      int
 
 	// logger
 	logger log.Logger
@@ -181,12 +187,20 @@ var (
 
 	// Clustering
 	ClusteringEnabled                        bool
+	ClusteringBackend                        string
+	ClusteringGossipSecret                   string
 	MaxAlertEvalTimeLimitInSeconds           int64 = DEFAULT_ALERT_EVALTIME_LIMIT
 	MaxMissingAlertCount                     int   = DEFAULT_MISSING_ALERT_COUNT
 	DefaultMissingAlertsDelay                int64 = DEFAULT_MISSING_ALERTS_DELAY
 	DefaultMissingAlertsSchedularTimeMinutes int   = DEFAULT_MISSING_ALERTS_SCHEDULAR_TIME_MINUTES
 	ClusteringCleanupPeriod                  int
 	ClusteringHBRetention                    int
+	ClusteringVirtualNodes                   int = DEFAULT_CLUSTERING_VIRTUAL_NODES
+	ClusteringDrainTimeout                   int = DEFAULT_CLUSTERING_DRAIN_TIMEOUT
+	ClusteringInvalidationPoll               int = DEFAULT_CLUSTERING_INVALIDATION_POLL
+	ClusteringCleanupSchedule                string
+	ClusteringMissingAlertsSchedule          string
+	ClusteringHBRetentionSchedule            string
 	AnnotationRetention                      int
 )
 
@@ -596,16 +610,13 @@ func NewConfigContext(args *CommandLineArgs) error {
 	alerting := Cfg.Section("alerting")
 	AlertingEnabled = alerting.Key("enabled").MustBool(true)
 	ExecuteAlerts = alerting.Key("execute_alerts").MustBool(true)
+	AlertingNotificationMaxAttempts = alerting.Key("notification_max_attempts").MustInt(3)
+	AlertingNotificationRetryBackoff = alerting.Key("notification_retry_backoff").MustInt(1)
+	AlertingNotificationTimeout = alerting.Key("notification_timeout").MustInt(30)
 
-	clustering := Cfg.Section("clustering")
-	ClusteringEnabled = clustering.Key("enabled").MustBool(true)
-	MaxAlertEvalTimeLimitInSeconds = clustering.Key("max_alert_evaltime_limit_seconds").MustInt64(DEFAULT_ALERT_EVALTIME_LIMIT)
-	MaxMissingAlertCount = clustering.Key("max_missing_alert_count").MustInt(DEFAULT_MISSING_ALERT_COUNT)
-	DefaultMissingAlertsDelay = clustering.Key("default_missing_alerts_delay").MustInt64(DEFAULT_MISSING_ALERTS_DELAY)
-	DefaultMissingAlertsSchedularTimeMinutes = clustering.Key("default_missing_alerts_schedular_time_minutes").MustInt(DEFAULT_MISSING_ALERTS_SCHEDULAR_TIME_MINUTES)
-	ClusteringCleanupPeriod = clustering.Key("cleanup_period").MustInt(DEFAULT_CLUSTERING_CLEANUP_PERIOD)
-	ClusteringHBRetention = clustering.Key("hb_retention_period").MustInt(DEFAULT_CLUSTERING_HB_RETENSION_PERIOD)
-	AnnotationRetention = clustering.Key("annotation_retention_period").MustInt(DEFAULT_ANNOTATION_RETENSION_PERIOD)
+	if err := readClusteringSettings(); err != nil {
+		return err
+	}
 
 	readSessionConfig()
 	readSmtpSettings()
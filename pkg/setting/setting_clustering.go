@@ -0,0 +1,42 @@
+package setting
+
+import (
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/cron"
+)
+
+func readClusteringSettings() error {
+	clustering := Cfg.Section("clustering")
+	ClusteringEnabled = clustering.Key("enabled").MustBool(true)
+	ClusteringBackend = clustering.Key("backend").In("sql", []string{"sql", "gossip"})
+	ClusteringGossipSecret = clustering.Key("gossip_secret").String()
+	if ClusteringBackend == "gossip" && ClusteringGossipSecret == "" {
+		return fmt.Errorf("[clustering] gossip_secret is required with the gossip backend")
+	}
+	MaxAlertEvalTimeLimitInSeconds = clustering.Key("max_alert_evaltime_limit_seconds").MustInt64(DEFAULT_ALERT_EVALTIME_LIMIT)
+	MaxMissingAlertCount = clustering.Key("max_missing_alert_count").MustInt(DEFAULT_MISSING_ALERT_COUNT)
+	DefaultMissingAlertsDelay = clustering.Key("default_missing_alerts_delay").MustInt64(DEFAULT_MISSING_ALERTS_DELAY)
+	DefaultMissingAlertsSchedularTimeMinutes = clustering.Key("default_missing_alerts_schedular_time_minutes").MustInt(DEFAULT_MISSING_ALERTS_SCHEDULAR_TIME_MINUTES)
+	ClusteringCleanupPeriod = clustering.Key("cleanup_period").MustInt(DEFAULT_CLUSTERING_CLEANUP_PERIOD)
+	ClusteringHBRetention = clustering.Key("hb_retention_period").MustInt(DEFAULT_CLUSTERING_HB_RETENSION_PERIOD)
+	ClusteringVirtualNodes = clustering.Key("virtual_nodes").MustInt(DEFAULT_CLUSTERING_VIRTUAL_NODES)
+	ClusteringDrainTimeout = clustering.Key("drain_timeout_seconds").MustInt(DEFAULT_CLUSTERING_DRAIN_TIMEOUT)
+	ClusteringInvalidationPoll = clustering.Key("invalidation_poll_seconds").MustInt(DEFAULT_CLUSTERING_INVALIDATION_POLL)
+	AnnotationRetention = clustering.Key("annotation_retention_period").MustInt(DEFAULT_ANNOTATION_RETENSION_PERIOD)
+	// cron schedules, the defaults follow the older period settings
+	ClusteringCleanupSchedule = clustering.Key("cleanup_schedule").MustString(fmt.Sprintf("0 */%d * * *", ClusteringCleanupPeriod))
+	ClusteringMissingAlertsSchedule = clustering.Key("missing_alerts_schedule").MustString(fmt.Sprintf("*/%d * * * *", DefaultMissingAlertsSchedularTimeMinutes))
+	ClusteringHBRetentionSchedule = clustering.Key("hb_retention_schedule").MustString(ClusteringCleanupSchedule)
+	schedules := []struct{ key, spec string }{
+		{"cleanup_schedule", ClusteringCleanupSchedule},
+		{"missing_alerts_schedule", ClusteringMissingAlertsSchedule},
+		{"hb_retention_schedule", ClusteringHBRetentionSchedule},
+	}
+	for _, schedule := range schedules {
+		if _, err := cron.Parse(schedule.spec); err != nil {
+			return fmt.Errorf("Invalid [clustering] %v: %v", schedule.key, err)
+		}
+	}
+	return nil
+}
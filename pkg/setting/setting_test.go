@@ -123,6 +123,39 @@ func TestLoadingSettings(t *testing.T) {
 			}
 		})
 
+		Convey("Clustering job schedules default to the cleanup and missing alerts periods", func() {
+			NewConfigContext(&CommandLineArgs{
+				HomePath: "../../",
+			})
+			Cfg.Section("clustering").Key("cleanup_period").SetValue("6")
+
+			So(readClusteringSettings(), ShouldBeNil)
+			So(ClusteringCleanupSchedule, ShouldEqual, "0 */6 * * *")
+			So(ClusteringHBRetentionSchedule, ShouldEqual, "0 */6 * * *")
+			So(ClusteringMissingAlertsSchedule, ShouldEqual, "*/10 * * * *")
+		})
+
+		Convey("Invalid clustering job schedule is refused", func() {
+			NewConfigContext(&CommandLineArgs{
+				HomePath: "../../",
+			})
+			Cfg.Section("clustering").Key("missing_alerts_schedule").SetValue("61 * * * *")
+
+			So(readClusteringSettings(), ShouldNotBeNil)
+		})
+
+		Convey("Gossip backend without a secret is refused", func() {
+			NewConfigContext(&CommandLineArgs{
+				HomePath: "../../",
+			})
+			Cfg.Section("clustering").Key("backend").SetValue("gossip")
+			So(readClusteringSettings(), ShouldNotBeNil)
+
+			Cfg.Section("clustering").Key("gossip_secret").SetValue("secret")
+			So(readClusteringSettings(), ShouldBeNil)
+			So(ClusteringGossipSecret, ShouldEqual, "secret")
+		})
+
 		Convey("Can use environment variables in config values", func() {
 			if runtime.GOOS == "windows" {
 				os.Setenv("GF_DATA_PATH", `c:\tmp\env_override`)
@@ -31,6 +31,10 @@ var alertStateSortScore = {
 var evalFunctions = [
   {text: 'IS ABOVE', value: 'gt'},
   {text: 'IS BELOW', value: 'lt'},
+  {text: 'IS ABOVE OR EQUAL TO', value: 'gte'},
+  {text: 'IS BELOW OR EQUAL TO', value: 'lte'},
+  {text: 'IS EQUAL TO', value: 'eq'},
+  {text: 'IS NOT EQUAL TO', value: 'ne'},
   {text: 'IS OUTSIDE RANGE', value: 'outside_range'},
   {text: 'IS WITHIN RANGE', value: 'within_range'},
   {text: 'HAS NO VALUE' , value: 'no_value'}
@@ -49,6 +53,15 @@ var reducerTypes = [
   {text: 'count()', value: 'count'},
   {text: 'last()', value: 'last'},
   {text: 'median()', value: 'median'},
+  {text: 'p90()', value: 'p90'},
+  {text: 'p95()', value: 'p95'},
+  {text: 'p99()', value: 'p99'},
+  {text: 'percentile()', value: 'percentile'},
+  {text: 'stddev()', value: 'stddev'},
+  {text: 'diff()', value: 'diff'},
+  {text: 'percent_diff()', value: 'percent_diff'},
+  {text: 'count_non_null()', value: 'count_non_null'},
+  {text: 'rate()', value: 'rate'},
 ];
 
 var noDataModes = [
@@ -64,6 +77,11 @@ var executionErrorModes = [
 ];
 
 function createReducerPart(model) {
+  if (model.type === 'percentile') {
+    var percentileDef = new QueryPartDef({type: model.type, params: [{name: 'percentile', type: 'number'}], defaultParams: [95]});
+    return new QueryPart(model, percentileDef);
+  }
+
   var def = new QueryPartDef({type: model.type, defaultParams: []});
   return new QueryPart(model, def);
 }
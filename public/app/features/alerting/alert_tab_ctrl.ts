@@ -287,6 +287,7 @@ export class AlertTabCtrl {
     switch (evt.name) {
       case "action": {
         conditionModel.source.reducer.type = evt.action.value;
+        conditionModel.source.reducer.params = null;
         conditionModel.reducerPart = alertDef.createReducerPart(conditionModel.source.reducer);
         break;
       }
@@ -343,24 +344,43 @@ export class AlertTabCtrl {
     this.panelCtrl.render();
   }
 
+  hasEpsilon(evaluator) {
+    return ['eq', 'ne', 'gte', 'lte'].indexOf(evaluator.type) !== -1;
+  }
+
   evaluatorTypeChanged(evaluator) {
     // ensure params array is correct length
     switch (evaluator.type) {
       case "lt":
         case "gt": {
         evaluator.params = [evaluator.params[0]];
+        delete evaluator.epsilon;
+        break;
+      }
+      case "lte":
+        case "gte":
+        case "eq":
+        case "ne": {
+        evaluator.params = [evaluator.params[0]];
         break;
       }
       case "within_range":
         case "outside_range": {
         evaluator.params = [evaluator.params[0], evaluator.params[1]];
+        delete evaluator.epsilon;
         break;
       }
       case "no_value": {
         evaluator.params = [];
+        delete evaluator.epsilon;
       }
     }
 
+    if (evaluator.type !== "within_range" && evaluator.type !== "outside_range") {
+      delete evaluator.lowerInclusive;
+      delete evaluator.upperInclusive;
+    }
+
     this.evaluatorParamsChanged();
   }
 
@@ -20,7 +20,9 @@ export class AlertNotificationEditCtrl {
       autoResolve: true,
       uploadImage: true,
     },
-    isDefault: false
+    isDefault: false,
+    sendReminder: false,
+    frequency: '15m',
   };
 
   /** @ngInject */
@@ -25,6 +25,27 @@ describe('ThresholdMapper', () => {
     });
   });
 
+  describe('with greater than or equal evaluator', () => {
+    it('can mapp query conditions to thresholds', () => {
+      var panel: any = {
+        type: 'graph',
+        alert: {
+          conditions: [
+            {
+              type: 'query',
+              evaluator: { type: 'gte', params: [100], epsilon: 0.5 }
+            }
+          ]
+        }
+      };
+
+      var updated = ThresholdMapper.alertToGraphThresholds(panel);
+      expect(updated).to.be(true);
+      expect(panel.thresholds[0].op).to.be('gt');
+      expect(panel.thresholds[0].value).to.be(100);
+    });
+  });
+
   describe('with outside range evaluator', () => {
     it('can mapp query conditions to thresholds', () => {
       var panel: any = {
@@ -18,12 +18,14 @@ export class ThresholdMapper {
       var thresholds = panel.thresholds = [];
 
       switch (evaluator.type) {
-        case "gt": {
+        case "gt":
+        case "gte": {
           let value = evaluator.params[0];
           thresholds.push({value: value, op: 'gt'});
           break;
         }
-        case "lt": {
+        case "lt":
+        case "lte": {
           let value = evaluator.params[0];
           thresholds.push({value: value, op: 'lt'});
           break;